@@ -0,0 +1,72 @@
+package clone
+
+// accessor is a user-defined pair of functions to get and to modify the
+// value of unexported fields through the public API of the structure
+type accessor struct {
+	name	string
+	get		func(any) any
+	mutate	func(any)
+}
+
+/*
+AddAccessor adds a user-defined accessor of the value reachable through the
+public API of the structure, for example, an unexported field returned by an
+exported getter method. It extends the verification to the values that cannot
+be changed directly, because they are stored in unexported fields.
+
+The get function must return the value for the structure passed as the
+argument, the mutate function must modify the value returned by get in place
+(e.g. modify elements of the returned slice or map). The name is used to
+identify the accessor in error messages:
+
+  sv.AddAccessor("Items",
+      func(x any) any { return x.(*Container).Items() },
+      func(v any) { v.([]int)[0]++ },
+  )
+
+After the verification of fields, for each accessor the clone of the original
+object is created, the value returned by get for the clone is modified by
+mutate. Then the get result for the original must be equal to the get result
+for the reference, and must be different from the get result for the clone.
+Note that the unexported fields are not filled automatically, so the creator
+function must initialize them with distinct storage on each call.
+*/
+func (sv *StructVerifier) AddAccessor(name string, get func(any) any, mutate func(any)) *StructVerifier {
+	sv.accessors = append(sv.accessors, accessor{name: name, get: get, mutate: mutate})
+	return sv
+}
+
+// verifyAccessors verifies the values reachable through the user-defined accessors
+func (sv *StructVerifier) verifyAccessors(orig, ref any) error {
+	for _, acc := range sv.accessors {
+		clone, err := sv.callCloner(orig, acc.name)
+		if err != nil {
+			return err
+		}
+
+		if !sv.equal(acc.get(orig), acc.get(clone)) {
+			return &ErrSVCloneOrigNotEqual{newErrSV("value of accessor %q of the newly created clone is not" +
+				" the same as of the original: orig - %s, clone - %s",
+				acc.name, sv.dump(acc.get(orig)), sv.dump(acc.get(clone)))}
+		}
+
+		// Modify the value of the clone
+		acc.mutate(acc.get(clone))
+
+		// Compare the original and the reference - they should be the same
+		if !sv.equal(acc.get(orig), acc.get(ref)) {
+			return &ErrSVOrigChanged{newErrSV("value of accessor %q of the ORIGINAL (%s) is DIFFERENT from" +
+				" the REFERENCE (%s) after the CLONE value has been CHANGED",
+				acc.name, sv.dump(acc.get(orig)), sv.dump(acc.get(ref)))}
+		}
+
+		// Compare the clone and the original - they should NOT be the same
+		if sv.equal(acc.get(orig), acc.get(clone)) {
+			return &ErrSVCloneOrigEqual{newErrSV("value of accessor %q of the CLONE has been UPDATED" +
+				" but it is EQUAL the ORIGINAL value: %s", acc.name, sv.dump(acc.get(clone)))}
+		}
+	}
+
+	// OK
+	return nil
+}
@@ -0,0 +1,48 @@
+package clone
+
+import (
+	"errors"
+	"testing"
+)
+
+type testAccessorStruct struct {
+	Name	string
+	items	[]int
+}
+
+func (s *testAccessorStruct) Items() []int {
+	return s.items
+}
+
+func newAccessorVerifier(shareItems bool) *StructVerifier {
+	return NewStructVerifier(
+		func() any { return &testAccessorStruct{items: []int{1, 2, 3}} },
+		func(x any) any {
+			orig := x.(*testAccessorStruct)	//nolint:forcetypeassert
+			rv := *orig
+			if !shareItems {
+				rv.items = append([]int(nil), orig.items...)
+			}
+			return &rv
+		},
+	).AddAccessor("Items",
+		func(x any) any { return x.(*testAccessorStruct).Items() },	//nolint:forcetypeassert
+		func(v any) { v.([]int)[0]++ },	//nolint:forcetypeassert
+	)
+}
+
+func TestAddAccessor(t *testing.T) {
+	if err := newAccessorVerifier(false).Verify(); err != nil {
+		t.Errorf("verification with accessors failed: %v", err)
+	}
+
+	err := newAccessorVerifier(true).Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the unexported slice")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
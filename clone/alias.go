@@ -0,0 +1,164 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// checkShared checks that the field of the clone does not share pointers and
+// backing arrays of slices with the same field of the original structure
+func (sv *StructVerifier) checkShared(orig, clone any, field string) error {
+	ov, cv := reflect.ValueOf(orig).Elem(), reflect.ValueOf(clone).Elem()
+	if ov.Type() != cv.Type() {
+		// Nothing to check
+		return nil
+	}
+
+	of, cf := fieldByPath(ov, field), fieldByPath(cv, field)
+
+	if path, shared := sv.findShared(of, cf, field, 0, samePointer); shared {
+		return &ErrSVSharedPointer{newErrSV("CLONE field %q shares the pointer %q with the ORIGINAL: %s",
+			field, path, sv.dump(clone))}
+	}
+
+	if path, shared := sv.findShared(of, cf, field, 0, hiddenOverlap); shared {
+		return &ErrSVSliceOverlap{newErrSV("CLONE field %q has the slice %q stored in the capacity of the" +
+			" ORIGINAL slice beyond its length, appending to the original overwrites the clone: %s",
+			field, path, sv.dump(clone))}
+	}
+
+	return nil
+}
+
+// samePointer reports whether orig and clone are the same non-nil pointers
+func samePointer(orig, clone reflect.Value) bool {
+	return orig.Kind() == reflect.Pointer && !orig.IsNil() && !clone.IsNil() && orig.Pointer() == clone.Pointer()
+}
+
+// hiddenOverlap reports whether orig and clone are slices, backing arrays of
+// which overlap only beyond their lengths, e.g. the clone is appended to the
+// spare capacity of the original. Elements of such slices are not shared, so
+// changes of the clone elements are not visible through the original
+func hiddenOverlap(orig, clone reflect.Value) bool {
+	if orig.Kind() != reflect.Slice || orig.IsNil() || clone.IsNil() || orig.Pointer() == clone.Pointer() {
+		return false
+	}
+
+	size := orig.Type().Elem().Size()
+	if size == 0 {
+		// No storage to share
+		return false
+	}
+
+	overlap := func(aPtr uintptr, aLen int, bPtr uintptr, bLen int) bool {
+		return aLen != 0 && bLen != 0 &&
+			aPtr < bPtr + uintptr(bLen) * size && bPtr < aPtr + uintptr(aLen) * size
+	}
+
+	// Overlapping of visible elements is revealed by the change of the clone
+	return !overlap(orig.Pointer(), orig.Len(), clone.Pointer(), clone.Len()) &&
+		overlap(orig.Pointer(), orig.Cap(), clone.Pointer(), clone.Cap())
+}
+
+// findShared walks through the pointers, exported structure fields, elements
+// of slices and arrays and map values of orig and clone and returns the path to
+// the first pair of values matching the shared function. Each level of pointer
+// indirection is denoted by * in the path, elements are denoted by [index] and
+// map values are denoted by [key]
+func (sv *StructVerifier) findShared(orig, clone reflect.Value, path string, depth int,
+		shared func(orig, clone reflect.Value) bool) (string, bool) {
+	if depth > sv.maxDepth {
+		// Too deep, stop here
+		return "", false
+	}
+
+	if shared(orig, clone) {
+		return path, true
+	}
+
+	switch orig.Kind() { //nolint:exhaustive	// other kinds do not contain pointers to check
+	case reflect.Pointer:
+		if orig.IsNil() || clone.IsNil() || orig.Pointer() == clone.Pointer() {
+			return "", false
+		}
+
+		return sv.findShared(orig.Elem(), clone.Elem(), "*" + path, depth + 1, shared)
+
+	case reflect.Interface:
+		if orig.IsNil() || clone.IsNil() || orig.Elem().Type() != clone.Elem().Type() {
+			return "", false
+		}
+
+		return sv.findShared(orig.Elem(), clone.Elem(), path, depth + 1, shared)
+
+	case reflect.Struct:
+		// Check values stored in atomic wrappers
+		if ov, ok := atomicLoad(orig); ok {
+			cv, _ := atomicLoad(clone)
+			return sv.findShared(ov, cv, path, depth + 1, shared)
+		}
+
+		// Check values stored in sync.Map values
+		if om, ok := syncMapSnapshot(orig); ok {
+			cm, _ := syncMapSnapshot(clone)
+			return sv.findShared(om, cm, path, depth + 1, shared)
+		}
+
+		for i := 0; i < orig.NumField(); i++ {
+			name := orig.Type().Field(i).Name
+			if !verifiable(orig.Type().Field(i)) {
+				continue
+			}
+
+			if p, ok := sv.findShared(orig.Field(i), clone.Field(i), path + "." + name, depth + 1, shared); ok {
+				return p, true
+			}
+		}
+
+	case reflect.Slice:
+		if orig.IsNil() || clone.IsNil() || orig.Pointer() == clone.Pointer() {
+			// Nothing to check or the whole slice is shared, that
+			// is revealed by the change of the clone
+			return "", false
+		}
+		fallthrough
+
+	case reflect.Array:
+		for i := 0; i < orig.Len() && i < clone.Len(); i++ {
+			if p, ok := sv.findShared(orig.Index(i), clone.Index(i), fmt.Sprintf("%s[%d]", path, i), depth + 1, shared); ok {
+				return p, true
+			}
+		}
+
+	case reflect.Map:
+		if orig.IsNil() || clone.IsNil() {
+			return "", false
+		}
+
+		for _, key := range sortedKeys(orig) {
+			cv := clone.MapIndex(key)
+			if !cv.IsValid() {
+				// No such key in the clone
+				continue
+			}
+
+			if p, ok := sv.findShared(orig.MapIndex(key), cv, fmt.Sprintf("%s[%v]", path, key), depth + 1, shared); ok {
+				return p, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// sortedKeys returns keys of the map m sorted by their string representation,
+// it is used to get the reproducible order of the map traversal
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	return keys
+}
@@ -0,0 +1,130 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testNode struct {
+	Name	string
+	Vals	[]int
+}
+
+type testNodeMap struct {
+	Nodes	map[string]*testNode
+}
+
+// cloneNodeMap returns a clone of testNodeMap, if shareNodes is true,
+// the map is copied but the nodes are shared with the original
+func cloneNodeMap(x any, shareNodes bool) any {
+	orig, ok := x.(*testNodeMap)
+	if !ok {
+		panic(fmt.Sprintf("unsupported type to clone - %T, want - *testNodeMap", x))
+	}
+
+	rv := &testNodeMap{Nodes: make(map[string]*testNode, len(orig.Nodes))}
+	for k, node := range orig.Nodes {
+		if shareNodes {
+			rv.Nodes[k] = node
+			continue
+		}
+
+		n := *node
+		n.Vals = make([]int, len(node.Vals))
+		copy(n.Vals, node.Vals)
+		rv.Nodes[k] = &n
+	}
+
+	return rv
+}
+
+func TestSharedMapValuePointers(t *testing.T) {
+	if err := NewStructVerifier(
+		func() any { return &testNodeMap{} },
+		func(x any) any { return cloneNodeMap(x, false) },
+	).Verify(); err != nil {
+		t.Errorf("verification of map with pointer values failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &testNodeMap{} },
+		func(x any) any { return cloneNodeMap(x, true) },
+	).Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares map values with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestSharedMapValuesChanged(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testNodeMap{} },
+		func(x any) any { return cloneNodeMap(x, true) },
+	)
+
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		t.Fatalf("cannot fill original and reference structures: %v", err)
+	}
+
+	// Changing of the shared node through the clone must be detected even without pointers check
+	clone := cloneNodeMap(orig, true)
+	if err := sv.autoChange(clone, "Nodes"); err != nil {
+		t.Fatalf("cannot change clone: %v", err)
+	}
+	if sv.equal(orig, ref) {
+		t.Errorf("changing of the shared node in the clone did not change the original")
+	}
+}
+
+type testSpare struct {
+	Vals	[]int
+	Name	string
+}
+
+// spareSetter fills []int fields with slices that leave the spare capacity
+func spareSetter() Setter {
+	n := 0
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().([]int); !ok {
+			return nil
+		}
+		n++
+		return append(make([]int, 0, 8), n, n + 1)
+	}
+}
+
+func TestSliceOverlap(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testSpare{} }, func(x any) any {
+		orig := x.(*testSpare)	//nolint:forcetypeassert
+		return &testSpare{Vals: append([]int(nil), orig.Vals...), Name: orig.Name}
+	}).AddSetters(spareSetter)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the correct clone failed: %v", err)
+	}
+}
+
+func TestSliceOverlapSpare(t *testing.T) {
+	// The clone is appended to the spare capacity of the original
+	sv := NewStructVerifier(func() any { return &testSpare{} }, func(x any) any {
+		orig := x.(*testSpare)	//nolint:forcetypeassert
+		return &testSpare{Vals: append(orig.Vals[len(orig.Vals):], orig.Vals...), Name: orig.Name}
+	}).AddSetters(spareSetter)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone is stored in the spare capacity of the original")
+	case errors.As(err, new(*ErrSVSliceOverlap)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSliceOverlap", err, err)
+	}
+}
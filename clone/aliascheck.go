@@ -0,0 +1,76 @@
+package clone
+
+import (
+	"reflect"
+)
+
+// ErrSVAliasCheck represents an error that occurs when a user-defined aliasing
+// check fails, see [StructVerifier.AddAliasCheck]. Field contains the name of
+// the verified field, Path contains the path to the checked value. The error
+// returned by the check is wrapped.
+type ErrSVAliasCheck struct {
+	structVerifierError
+	Field	string
+	Path	string
+}
+
+/*
+AddAliasCheck adds the user-defined check of values of type fieldType. The
+equality of the clone and the original does not prove that the clone does not
+share memory with the original, e.g. buffers accessible only through methods
+or slices of types that cannot be changed. The check function takes the values
+of the original and the clone at the same path and returns an error if the
+clone shares memory with the original, for example:
+
+  sv.AddAliasCheck(reflect.TypeOf([]byte(nil)), func(orig, clone reflect.Value) error {
+      if orig.Len() != 0 && orig.Pointer() == clone.Pointer() {
+          return errors.New("buffer is shared")
+      }
+      return nil
+  })
+
+Checks are performed after the newly created clone is confirmed to be equal
+to the original, for values at any level of nesting of each verified field,
+in the same way as the check of shared pointers. If a check fails, the
+*[ErrSVAliasCheck] error is returned. Adding a check for the same type again
+replaces the previous one.
+*/
+func (sv *StructVerifier) AddAliasCheck(fieldType reflect.Type,
+		check func(origField, cloneField reflect.Value) error) *StructVerifier {
+	if sv.aliasChecks == nil {
+		sv.aliasChecks = map[reflect.Type]func(origField, cloneField reflect.Value) error{}
+	}
+	sv.aliasChecks[fieldType] = check
+
+	return sv
+}
+
+// runAliasChecks performs the user-defined aliasing checks of the field of
+// the clone and the same field of the original structure
+func (sv *StructVerifier) runAliasChecks(orig, clone any, field string) error {
+	ov, cv := reflect.ValueOf(orig).Elem(), reflect.ValueOf(clone).Elem()
+	if len(sv.aliasChecks) == 0 || ov.Type() != cv.Type() {
+		// Nothing to check
+		return nil
+	}
+
+	var checkErr error
+	path, failed := sv.findShared(fieldByPath(ov, field), fieldByPath(cv, field), field, 0,
+		func(orig, clone reflect.Value) bool {
+			check, ok := sv.aliasChecks[orig.Type()]
+			if !ok {
+				return false
+			}
+			checkErr = check(orig, clone)
+			return checkErr != nil
+		})
+	if !failed {
+		return nil
+	}
+
+	return &ErrSVAliasCheck{
+		structVerifierError:	newErrSV("alias check of %q failed for CLONE field %q: %w", path, field, checkErr),
+		Field:					field,
+		Path:					path,
+	}
+}
@@ -0,0 +1,65 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testPacket struct {
+	Header	string
+	Payload	[]byte
+}
+
+var errSharedPayload = errors.New("payload is shared")
+
+// checkPayload reports payloads of the clone sharing arrays with the original
+func checkPayload(orig, clone reflect.Value) error {
+	if orig.Len() != 0 && orig.Pointer() == clone.Pointer() {
+		return errSharedPayload
+	}
+	return nil
+}
+
+func TestAddAliasCheck(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testPacket{} },
+		func(x any) any {
+			rv := *x.(*testPacket)	//nolint:forcetypeassert
+			rv.Payload = append([]byte(nil), rv.Payload...)
+			return &rv
+		},
+		WithAliasCheck(reflect.TypeOf([]byte(nil)), checkPayload),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the deep clone failed: %v", err)
+	}
+}
+
+func TestAddAliasCheckShared(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testPacket{} },
+		func(x any) any {
+			rv := *x.(*testPacket)	//nolint:forcetypeassert
+			return &rv
+		},
+		WithAliasCheck(reflect.TypeOf([]byte(nil)), checkPayload),
+	)
+
+	err := sv.Verify()
+	var aErr *ErrSVAliasCheck
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the payload with the original")
+	case errors.As(err, &aErr):
+		if aErr.Field != "Payload" || aErr.Path != "Payload" {
+			t.Errorf("got field %q and path %q, want - %q", aErr.Field, aErr.Path, "Payload")
+		}
+		if !errors.Is(err, errSharedPayload) {
+			t.Errorf("error of the check is not wrapped: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVAliasCheck", err, err)
+	}
+}
@@ -0,0 +1,165 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// atomicPkg is the package path of the atomic wrapper types
+const atomicPkg = "sync/atomic"
+
+// atomicTypes caches results of the hasAtomic function
+var atomicTypes sync.Map // map[reflect.Type]bool
+
+// isAtomic returns true if t is one of the sync/atomic wrapper types,
+// such as atomic.Int64, atomic.Bool, atomic.Value or atomic.Pointer[T]
+func isAtomic(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != atomicPkg {
+		return false
+	}
+
+	pt := reflect.PointerTo(t)
+	_, okLoad := pt.MethodByName("Load")
+	_, okStore := pt.MethodByName("Store")
+
+	return okLoad && okStore
+}
+
+// hasAtomic returns true if values of type t may contain atomic wrappers, such
+// values cannot be compared by reflect.DeepEqual, because the atomic.Pointer
+// type holds the pointer in the unexported field compared by identity
+func hasAtomic(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	if v, ok := atomicTypes.Load(t); ok {
+		return v.(bool) //nolint:forcetypeassert	// only bool values are stored
+	}
+
+	rv := findType(t, isAtomic, map[reflect.Type]bool{})
+	atomicTypes.Store(t, rv)
+
+	return rv
+}
+
+// findType walks through the type t to find types matching the match function
+func findType(t reflect.Type, match func(reflect.Type) bool, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if match(t) {
+		return true
+	}
+
+	switch t.Kind() { //nolint:exhaustive	// other kinds cannot contain other types
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if findType(t.Field(i).Type, match, seen) {
+				return true
+			}
+		}
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return findType(t.Elem(), match, seen)
+	case reflect.Map:
+		return findType(t.Key(), match, seen) || findType(t.Elem(), match, seen)
+	}
+
+	return false
+}
+
+// atomicLoad returns the value stored in the atomic wrapper v. It returns false
+// if v is not an atomic wrapper or its value cannot be obtained, e.g. v is
+// stored in an unexported field
+func atomicLoad(v reflect.Value) (reflect.Value, bool) {
+	if !isAtomic(v.Type()) || !v.CanInterface() {
+		return reflect.Value{}, false
+	}
+
+	if !v.CanAddr() {
+		// Need an addressable copy to call the method with pointer receiver
+		c := reflect.New(v.Type())
+		c.Elem().Set(v)
+		v = c.Elem()
+	}
+
+	return v.Addr().MethodByName("Load").Call(nil)[0], true
+}
+
+// atomicValue creates a new value of the atomic wrapper type of v, the stored
+// value is produced by the filler. It returns false if v is not an atomic wrapper
+func (fl *filler) atomicValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	if !isAtomic(v.Type()) {
+		return reflect.Value{}, false, nil
+	}
+
+	x := reflect.New(v.Type())
+	vt := x.MethodByName("Load").Type().Out(0)
+
+	var val reflect.Value
+	switch vt.Kind() { //nolint:exhaustive	// other kinds are filled by the filler
+	case reflect.Bool:
+		// Only one non-zero value is possible
+		val = reflect.ValueOf(true)
+
+	case reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// Use the int setter to produce distinct values of all integer types
+		iv, err := fl.value(reflect.New(reflect.TypeOf(0)).Elem(), path)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		val = iv.Convert(vt)
+
+	default:
+		// atomic.Pointer[T] and atomic.Value
+		var err error
+		if val, err = fl.value(reflect.New(vt).Elem(), path); err != nil {
+			return reflect.Value{}, true, err
+		}
+	}
+
+	if vt.Kind() == reflect.Interface && val.IsNil() {
+		return reflect.Value{}, true, fmt.Errorf("field %q of type %q cannot store nil value", path, v.Type())
+	}
+
+	x.MethodByName("Store").Call([]reflect.Value{val})
+
+	return x.Elem(), true, nil
+}
+
+// changeAtomic changes the value stored in the atomic wrapper v, it returns
+// false if v is not an atomic wrapper or the stored value cannot be changed
+func (ch *changer) changeAtomic(v reflect.Value) bool {
+	cur, ok := atomicLoad(v)
+	if !ok {
+		return false
+	}
+
+	// Make a changeable copy of the stored value
+	val := reflect.New(cur.Type()).Elem()
+	val.Set(cur)
+
+	switch val.Kind() { //nolint:exhaustive	// other kinds are changed by the changer
+	case reflect.Bool:
+		val.SetBool(!val.Bool())
+	case reflect.Int32, reflect.Int64:
+		val.SetInt(val.Int() + initialSeed)
+	case reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		val.SetUint(val.Uint() + initialSeed)
+	default:
+		// atomic.Pointer[T] and atomic.Value
+		if val.IsNil() || !ch.change(val) {
+			return false
+		}
+	}
+
+	if !v.CanAddr() {
+		return false
+	}
+	v.Addr().MethodByName("Store").Call([]reflect.Value{val})
+
+	return true
+}
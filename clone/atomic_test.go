@@ -0,0 +1,57 @@
+package clone
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type testAtomicStruct struct {
+	Count	atomic.Int64
+	Hits	atomic.Uint32
+	Ready	atomic.Bool
+	Conf	atomic.Pointer[[]int]
+}
+
+// cloneAtomicStruct returns a clone of testAtomicStruct, if shareConf
+// is true, the configuration pointer is shared with the original
+func cloneAtomicStruct(x any, shareConf bool) any {
+	orig := x.(*testAtomicStruct)	//nolint:forcetypeassert
+
+	rv := &testAtomicStruct{}
+	rv.Count.Store(orig.Count.Load())
+	rv.Hits.Store(orig.Hits.Load())
+	rv.Ready.Store(orig.Ready.Load())
+
+	if shareConf {
+		rv.Conf.Store(orig.Conf.Load())
+	} else if conf := orig.Conf.Load(); conf != nil {
+		c := append([]int(nil), *conf...)
+		rv.Conf.Store(&c)
+	}
+
+	return rv
+}
+
+func TestCloneAtomics(t *testing.T) {
+	if err := NewStructVerifier(
+		func() any { return &testAtomicStruct{} },
+		func(x any) any { return cloneAtomicStruct(x, false) },
+	).Verify(); err != nil {
+		t.Errorf("verification of structure with atomic fields failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &testAtomicStruct{} },
+		func(x any) any { return cloneAtomicStruct(x, true) },
+	).Verify()
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the atomic pointer")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
@@ -0,0 +1,56 @@
+package clone
+
+import (
+	"encoding"
+	"fmt"
+)
+
+/*
+BinaryCloner returns the cloner function that clones the structure by calling
+its MarshalBinary method and passing the result to the UnmarshalBinary method
+of a new instance created by the creator function. The cloner panics if the
+structure does not implement [encoding.BinaryMarshaler], the new instance does
+not implement [encoding.BinaryUnmarshaler] or the methods return errors, the
+panic is reported by the verification as *[ErrSVClonePanic].
+*/
+func BinaryCloner(creator CreatorFunc) ClonerFunc {
+	return func(x any) any {
+		m, ok := x.(encoding.BinaryMarshaler)
+		if !ok {
+			panic(fmt.Sprintf("%T does not implement encoding.BinaryMarshaler", x))
+		}
+		data, err := m.MarshalBinary()
+		if err != nil {
+			panic(fmt.Sprintf("cannot marshal %T to binary: %v", x, err))
+		}
+
+		rv := creator()
+		u, ok := rv.(encoding.BinaryUnmarshaler)
+		if !ok {
+			panic(fmt.Sprintf("%T does not implement encoding.BinaryUnmarshaler", rv))
+		}
+		if err := u.UnmarshalBinary(data); err != nil {
+			panic(fmt.Sprintf("cannot unmarshal binary to %T: %v", rv, err))
+		}
+
+		return rv
+	}
+}
+
+/*
+VerifyBinaryRoundTrip verifies cloning of the structure by the binary
+round-trip, see [BinaryCloner]. It works like [VerifyJSONRoundTrip]: the fields
+that the marshaler forgets to encode or the unmarshaler forgets to decode are
+reported by the *[ErrSVRoundTripDiverged] error, then the regular verification
+is run, for example:
+
+  err := clone.VerifyBinaryRoundTrip(func() any { return &Record{} }, false)
+*/
+func VerifyBinaryRoundTrip(creator CreatorFunc, nilEmptyEqual bool, opts ...Option) error {
+	sv := NewStructVerifierWith(creator, BinaryCloner(creator), opts...)
+	if nilEmptyEqual {
+		sv.TreatNilAndEmptyEqual()
+	}
+
+	return sv.verifyRoundTrip("binary")
+}
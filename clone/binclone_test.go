@@ -0,0 +1,78 @@
+package clone
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testBinRecord struct {
+	ID		int
+	Name	string
+	Scores	[]int
+	Note	string
+}
+
+// testBinPlain has the fields of testBinRecord without its methods
+type testBinPlain testBinRecord
+
+func (r *testBinRecord) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode((*testBinPlain)(r))
+	return buf.Bytes(), err
+}
+
+func (r *testBinRecord) UnmarshalBinary(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*testBinPlain)(r))
+}
+
+// testBinLossy forgets to marshal the Note field
+type testBinLossy testBinRecord
+
+func (r *testBinLossy) MarshalBinary() ([]byte, error) {
+	x := *r
+	x.Note = ""
+	return (*testBinRecord)(&x).MarshalBinary()
+}
+
+func (r *testBinLossy) UnmarshalBinary(data []byte) error {
+	return (*testBinRecord)(r).UnmarshalBinary(data)
+}
+
+func TestVerifyBinaryRoundTrip(t *testing.T) {
+	if err := VerifyBinaryRoundTrip(func() any { return &testBinRecord{} }, false); err != nil {
+		t.Errorf("verification of binary round-trip failed: %v", err)
+	}
+}
+
+func TestVerifyBinaryRoundTripLossy(t *testing.T) {
+	err := VerifyBinaryRoundTrip(func() any { return &testBinLossy{} }, false)
+
+	var rErr *ErrSVRoundTripDiverged
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the Note field is not marshaled")
+	case errors.As(err, &rErr):
+		if want := []string{"Note"}; !reflect.DeepEqual(rErr.Fields, want) {
+			t.Errorf("got diverged fields %q, want - %q", rErr.Fields, want)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVRoundTripDiverged", err, err)
+	}
+}
+
+func TestVerifyBinaryRoundTripNoMarshaler(t *testing.T) {
+	// Types without the marshaler cannot be cloned
+	err := VerifyBinaryRoundTrip(func() any { return &testBinPlain{} }, false)
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the type has no binary marshaler")
+	case errors.As(err, new(*ErrSVClonePanic)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVClonePanic", err, err)
+	}
+}
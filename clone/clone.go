@@ -1,6 +1,7 @@
 package clone
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -132,31 +133,127 @@ structure will have the same values as the original one.
 */
 type SetterCreator func() Setter
 
+/*
+SetterWithField defines the type of function that works like [Setter], but also
+takes the name of the filled field. It allows generating values depending on
+the field, e.g. a valid port number for the int field "Port":
+
+  func portSetter() clone.SetterWithField {
+      port := 8080
+      return func(name string, v reflect.Value) any {
+          if _, ok := v.Interface().(int); !ok || name != "Port" {
+              return nil
+          }
+          port++
+          return port
+      }
+  }
+
+For fields of nested structures, the name is the path to the field from the
+verified structure, e.g. "Server.Port". Values pointed by pointers get the name
+of the pointer field. Elements of slices and arrays get the path with the
+index, e.g. "Ports[1]", keys of maps get the path with the number of the key,
+e.g. "Limits(key #0)", and values of maps get the path with the key, e.g.
+"Limits[key_3]", so the setter of elements should check the prefix of the
+name, like strings.HasPrefix(name, "Ports["). Note that the values supported by
+the embedded setters, like []int or []string, are filled as a whole, so
+the setter is called for them only with the name of the field.
+*/
+type SetterWithField func(name string, v reflect.Value) any
+
+// SetterWithFieldCreator defines the type of function used to create
+// [SetterWithField] functions, see [SetterCreator] for the reasons.
+type SetterWithFieldCreator func() SetterWithField
+
 type StructVerifier struct {
 	creator	CreatorFunc
 	cloner	ClonerFunc
 
-	setters		[]SetterCreator	// user defined setters
-	changers	[]Changer		// user defined changers
+	setters			[]SetterCreator				// user defined setters
+	fieldSetters	[]SetterWithFieldCreator	// user defined setters taking field names
+	changers		[]Changer					// user defined changers
+
+	concretes	map[reflect.Type][]ConcreteProducer	// producers of interface values
+	optionals	map[reflect.Type]optional			// types with optional semantics
+	externals	map[reflect.Type]external			// structure types defined in other packages
+
+	aliasChecks	map[reflect.Type]func(origField, cloneField reflect.Value) error	// user-defined aliasing checks
+	methodChecks	[]methodCheck	// methods called on the original and clones
+
+	propagatePanics	bool	// do not recover panics of the cloner function
+
+	cmp	comparator	// values comparison settings
+
+	maxDepth	int	// maximum pointer indirection depth
+	state		SeedState	// initial state of embedded setters
+	lastState	*SeedState	// state reached by the last filling, nil if nothing filled
+	parallelism	int	// number of fields verified concurrently
+
+	strictSetters	bool	// fail if setters produce the same values for different fields
+	allFields		bool	// verify the clone with all fields changed at once
+	nilIfaces		bool	// verify the clone preserves nil interface fields
+	nilReceiver		bool	// verify the cloner returns nil for the nil pointer
+	insertKeys		bool	// verify key insertion into maps of the clone and the original
+	nilPointers		bool	// verify nil assignments to pointers of the clone
+	crossAlias		bool	// verify slices of the clone do not share backing arrays
+	sparse			bool	// verify clones of structures with a single populated field
+	emptyCap		bool	// verify clones of empty slices with nonzero capacity
+	deterministic	bool	// verify the cloner produces the same clones of the same original
+	accessors		[]accessor	// user-defined accessors of unexported fields
+	nestedCloners	bool		// verify Clone methods of nested structures
+	partial			bool			// skip fields of unsupported types
+	skipped			map[string]bool	// fields skipped in the partial mode
+	sharedFields	map[string]bool	// fields expected to be shared with the original
+	funcs			*funcCache		// functions filled into func values
+	mapOneEntry		bool			// change only one entry of maps
+	mapSlices		bool			// fill map[string]any fields with slice values too
+	mapNestDepth	int				// levels of map[string]any values nested into map[string]any fields
+	nonFinite		bool			// fill float fields with NaN and Inf values
+	shuffle			bool			// process fields in the random order
+	shuffleSeed		int64			// seed of the random order of fields
+	differ			Differ			// external comparison library, if set
+	repro			bool			// capture snippets reproducing failures
+
+	onFieldStart	func(field string)				// called before the field verification
+	onFieldDone		func(field string, err error)	// called after the field verification
+	warnings		[]error	// warnings of the last verification
+	maxErrLen		int		// maximum length of values rendered in errors
 }
 
 //
 // Errors
 //
 type structVerifierError struct {
-	err error
+	err		error
+	repro	string	// snippet reproducing the failure, see StructVerifier.CaptureRepro
 }
 func (esv structVerifierError) Error() string {
 	return esv.err.Error()
 }
+func (esv structVerifierError) Unwrap() error {
+	return errors.Unwrap(esv.err)
+}
 func newErrSV(format string, args ...any) structVerifierError {
-	return structVerifierError{fmt.Errorf(format, args...)}
+	return structVerifierError{err: fmt.Errorf(format, args...)}
 }
 type (
 	// ErrSVChange represents an error that occurs when the value of a field in the
 	// tested structure cannot be changed.
 	ErrSVChange struct { structVerifierError }
 
+	// ErrSVClonePanic represents an error that occurs when the cloner function
+	// panics. Field contains the name of the field being processed, Value
+	// contains the value returned by recover().
+	ErrSVClonePanic struct {
+		structVerifierError
+		Field	string
+		Value	any
+	}
+
+	// ErrSVCloneTypeMismatch represents an error that occurs when the cloner
+	// function returns a value of a type different from the type of the original.
+	ErrSVCloneTypeMismatch struct { structVerifierError }
+
 	// ErrSVCloneOrigEqual represents an error occurred when the initial value of a cloned
 	// structure field was not changed after the Setter function was applied to it.
 	ErrSVCloneOrigEqual struct { structVerifierError }
@@ -165,10 +262,36 @@ type (
 	// structures are different immediately after creation (before the clone changes).
 	ErrSVCloneOrigNotEqual struct { structVerifierError }
 
+	// ErrSVCloneNondeterministic represents an error that occurs when the cloner
+	// function produces different clones of the same original on different
+	// calls, see [StructVerifier.CheckDeterministic].
+	ErrSVCloneNondeterministic struct { structVerifierError }
+
 	// ErrSVFieldNotFound represents the error which occurs if a clone does not
 	// contain the original structure field.
 	ErrSVFieldNotFound struct { structVerifierError }
 
+	// ErrSVFieldsMismatch represents an error that occurs when the set of
+	// verified fields differs from the expected one, see
+	// [StructVerifier.VerifyExpectingFields]. Missing contains the expected
+	// fields that were not found, Unexpected contains the found fields that
+	// were not expected.
+	ErrSVFieldsMismatch struct {
+		structVerifierError
+		Missing		[]string
+		Unexpected	[]string
+	}
+
+	// ErrSVNilIfaceNotPreserved represents an error that occurs when a nil
+	// interface field of the original is not nil in the clone, see
+	// [StructVerifier.CheckNilInterfaces].
+	ErrSVNilIfaceNotPreserved struct { structVerifierError }
+
+	// ErrSVNilReceiverNotNil represents an error that occurs when the cloner
+	// function returns a non-nil value for the nil pointer to the structure,
+	// see [StructVerifier.CheckNilReceiver].
+	ErrSVNilReceiverNotNil struct { structVerifierError }
+
 	// ErrSVOrigChanged represents the error occurred when the initial structure
 	// (cloning source) was changed after modification of the cloned structure.
 	ErrSVOrigChanged struct { structVerifierError }
@@ -184,6 +307,40 @@ type (
 	// ErrSVRefOrigEqual represents an error if the original and the reference
 	// structures are different immediately after creation (before the clone changes).
 	ErrSVRefOrigEqual struct { structVerifierError }
+
+	// ErrSVRefOrigTypeMismatch represents an error that occurs when interface
+	// values of the original and the reference structures hold values of
+	// different types immediately after creation, e.g. a Setter function or
+	// a registered concrete producer returns values of different types on
+	// different fillings. Field contains the path to the value, OrigType and
+	// RefType contain the types of the values, nil for nil interfaces.
+	ErrSVRefOrigTypeMismatch struct {
+		structVerifierError
+		Field		string
+		OrigType	reflect.Type
+		RefType		reflect.Type
+	}
+
+	// ErrSVSharedPointer represents an error that occurs when a pointer of the
+	// cloned structure (at any level of indirection) is the same as the pointer
+	// of the original structure.
+	ErrSVSharedPointer struct { structVerifierError }
+
+	// ErrSVSliceOverlap represents an error that occurs when a slice of the
+	// cloned structure is stored in the backing array of the slice of the
+	// original structure beyond its length, e.g. the clone is appended to the
+	// spare capacity of the original. Such clone is overwritten by appending
+	// to the original.
+	ErrSVSliceOverlap struct { structVerifierError }
+
+	// ErrSVSetterTypeMismatch represents an error that occurs when a Setter
+	// function returns a value which type cannot be assigned to the field.
+	ErrSVSetterTypeMismatch struct { structVerifierError }
+
+	// ErrSVSetterNotVarying represents an error that occurs in the strict mode
+	// if two different fields of the same type have the same values after
+	// filling, see [StructVerifier.StrictSetters].
+	ErrSVSetterNotVarying struct { structVerifierError }
 )
 
 /*
@@ -197,8 +354,12 @@ See [StructVerifier.Verify] for how they are used during verification.
 */
 func NewStructVerifier(creator CreatorFunc, cloner ClonerFunc) *StructVerifier {
 	return &StructVerifier{
-		creator: creator,
-		cloner:	cloner,
+		creator:	creator,
+		cloner:		cloner,
+		maxDepth:	defaultMaxDepth,
+		maxErrLen:	defaultMaxErrorLen,
+		state:		newSeedState(0),
+		funcs:		&funcCache{},
 	}
 }
 
@@ -230,6 +391,113 @@ func (sv *StructVerifier) AddChangers(changers ...Changer) *StructVerifier {
 	return sv
 }
 
+/*
+AddSettersWithField adds user-defined [SetterWithFieldCreator] functions, the
+created [SetterWithField] functions get the name of the filled field. They take
+precedence over the functions added by [StructVerifier.AddSetters] and over the
+embedded Setter functions.
+*/
+func (sv *StructVerifier) AddSettersWithField(setters ...SetterWithFieldCreator) *StructVerifier {
+	sv.fieldSetters = append(sv.fieldSetters, setters...)
+	return sv
+}
+
+// ClearSetters removes all user-defined setter creators added by
+// [StructVerifier.AddSetters] and [StructVerifier.AddSettersWithField].
+// The embedded Setter functions are not affected.
+func (sv *StructVerifier) ClearSetters() *StructVerifier {
+	sv.setters = nil
+	sv.fieldSetters = nil
+	return sv
+}
+
+// ClearChangers removes all user-defined [Changer] functions added by
+// [StructVerifier.AddChangers]. The embedded Changer functions are not affected.
+func (sv *StructVerifier) ClearChangers() *StructVerifier {
+	sv.changers = nil
+	return sv
+}
+
+/*
+SetMapChangeAllEntries sets whether all entries of map fields are changed
+during verification. It is the default behavior, that guarantees that an entry
+shared between the clone and the original is detected regardless of the
+entries order. If all is false, only the entry with the first key in sorted
+order is changed, that is enough to detect the map itself shared with the
+original.
+*/
+func (sv *StructVerifier) SetMapChangeAllEntries(all bool) *StructVerifier {
+	sv.mapOneEntry = !all
+	return sv
+}
+
+/*
+SetMapSliceValues sets whether fields of the map[string]any type are filled
+with slice values ([]string and []int) along with int values. Such maps are
+common in real code, e.g. {"tags": []string{...}}, and a cloner copying the
+map but sharing the value slices is detected only if the map holds slices:
+the embedded changer changes elements of slice values in place.
+*/
+func (sv *StructVerifier) SetMapSliceValues(on bool) *StructVerifier {
+	sv.mapSlices = on
+	return sv
+}
+
+/*
+SetMapNestedValues sets the number of levels of map[string]any values nested
+into fields of the map[string]any type, like JSON-like configuration data. Each
+filled map holds the nested map by the "nested" key along with other values,
+the nested map holds the next one, and so on. The embedded changer changes
+nested maps in place, so a cloner copying the top-level map but sharing the
+nested ones is detected, the difference is reported by the full key path, like
+"Attrs[nested][b_b_b_]". Zero depth (default) disables nesting.
+*/
+func (sv *StructVerifier) SetMapNestedValues(depth int) *StructVerifier {
+	if depth < 0 {
+		depth = 0
+	}
+	sv.mapNestDepth = depth
+	return sv
+}
+
+/*
+OnFieldStart sets the hook function called by [StructVerifier.Verify] before
+the verification of each field, the name of the field is passed to the hook.
+It can be used to report the progress of verification of wide structures.
+
+If the parallelism is enabled (see [WithParallelism]), the hook is called
+concurrently from different goroutines.
+*/
+func (sv *StructVerifier) OnFieldStart(hook func(field string)) *StructVerifier {
+	sv.onFieldStart = hook
+	return sv
+}
+
+/*
+OnFieldDone sets the hook function called by [StructVerifier.Verify] after the
+verification of each field, the name of the field and the verification result
+(nil on success) are passed to the hook. Together with [StructVerifier.OnFieldStart]
+it can be used to measure the verification time of each field or to integrate
+with test reporters.
+
+If the parallelism is enabled (see [WithParallelism]), the hook is called
+concurrently from different goroutines.
+*/
+func (sv *StructVerifier) OnFieldDone(hook func(field string, err error)) *StructVerifier {
+	sv.onFieldDone = hook
+	return sv
+}
+
+/*
+PropagatePanics disables the recovery of panics raised by the cloner function.
+By default, [StructVerifier.Verify] converts such panic to the *[ErrSVClonePanic]
+error, after calling PropagatePanics the panic is propagated to the caller.
+*/
+func (sv *StructVerifier) PropagatePanics() *StructVerifier {
+	sv.propagatePanics = true
+	return sv
+}
+
 /*
 Verify performs the verification process. It returns an error if the structure
 clonning process is not correct.
@@ -249,9 +517,13 @@ During verification, the following objects are created and used:
 The verification process consists of:
 
   1. Creation of original and reference objects, compare them with each other -
-     they must be equal.
+     they must be equal. Also, different fields of the same type must have
+     different values, see [StructVerifier.StrictSetters].
   2. Creation of a clone object from the original object using the cloner function.
   3. Comparison of the original object with the clone - they must be equal.
+     Also, the pointers of the clone at any level of indirection must not be
+     the same as the pointers of the original object, and the slices of the
+     clone must not be stored in the spare capacity of the original slices.
   4. Automatically change the data of the exported fields of the clone object
      using the Setter functions that match the field types.
   5. Verification that the original object is the same as the reference one -
@@ -263,6 +535,22 @@ The verification process consists of:
      objects, or incorrect work of Changer-functions.
 
 Verification is considered successful when all the checks are passed.
+Additional verification phases can be enabled, see [StructVerifier.ChangeAllFields],
+[StructVerifier.CheckNilInterfaces], [StructVerifier.CheckNilReceiver],
+[StructVerifier.InsertMapKeys], [StructVerifier.CheckNilPointers],
+[StructVerifier.CheckCrossFieldAliasing], [StructVerifier.CheckSparseFields],
+[StructVerifier.CheckEmptyCapacity] and [StructVerifier.CheckDeterministic].
+Fields of optional types are verified in both populated and absent states, see
+[StructVerifier.RegisterOptional].
+Fields of structure types defined in other packages are filled by registered
+producers and their cloners are verified too, see [StructVerifier.RegisterExternal].
+User-defined aliasing checks are performed for each newly created clone, see
+[StructVerifier.AddAliasCheck], the results of registered methods are compared
+too, see [StructVerifier.AddMethodCheck].
+Fields intentionally shared with the original are checked in the opposite way,
+see [StructVerifier.ExpectShared].
+Fields are processed in the declaration order, unless the random order is
+set by [StructVerifier.SetShuffleFields].
 
 # Only exported fields cloning can be verified
 
@@ -273,60 +561,396 @@ fields by a code not related to the package of the verified structure.
 Your structure can contain non-exported fields, they will be skipped during
 verification.
 
+Exported fields of types sync.Mutex and sync.RWMutex (including embedded ones)
+are skipped too, because locks cannot be cloned meaningfully. The verifier never
+locks them, so the rest of the fields guarded by them are verified as usual.
+
+# Pointers, nested structures and containers
+
+Fields of pointer types (including multiple levels of indirection, like **T)
+and nested structures without appropriate Setter functions are filled
+automatically: memory for each level of indirection is allocated and the
+innermost value is filled using the Setter functions, the exported fields of
+the nested structures are filled the same way. Such fields are changed by
+changing the innermost values. The indirection depth is limited to prevent
+the processing of pathological types.
+
+Structures embedded by value (including unexported ones) are verified by their
+promoted exported fields, each of them is changed separately and reported by
+its path, like "B.Items". Structures embedded by pointer are verified as a
+single field.
+
+Slices, arrays and maps without appropriate Setter functions are filled by
+elements created by the Setter functions of their element (and key) types.
+Changing of such fields changes the last element of the slice or the array or
+all values of the map. Thus, arrays of slices (like [N][]int) are changed inside
+the last inner slice, that reveals inner slices shared with the original.
+Arrays of zero length hold nothing to change, so they are not verified.
+Pointers stored in slices, arrays and maps (like []*int) are compared by
+identity, so the clone must not share them with the original.
+
+Map values are not addressable, so values of maps of structures (like
+map[string]Item) are changed on copies, which are then stored back into the map.
+Since the copy of the structure shares its slices, maps and pointers with the
+map value, a clone copying the map entries but sharing their inner slices is
+detected, the difference is reported by the path like "Items[key].Tags[1]".
+
+Recursive types, like tree nodes with the Children []*Node and Parent *Node
+fields, are filled to a limited number of nested levels, deeper pointers of
+the same type are left nil. Values reachable by several paths, e.g. by
+back-references of cyclic structures verified by [StructVerifier.VerifyInstance],
+are changed only once, so the change terminates. Child nodes shared by the
+clone with the original are reported as pointers shared with the original.
+
+Fields of function types are filled by distinct functions that do nothing and
+return zero values. Functions cannot be deeply cloned, so copying of the
+function value is correct, such fields are compared but not changed.
+
+Fields of the sync.Map type (and pointers to it) are filled by entries with
+string keys and int values using the Store method, and changed by storing a
+changed value for an existing key. The entries are compared instead of the
+internal structures, so a clone that copies the sync.Map value or shares the
+pointer to it with the original is detected.
+
+Fields of named types (like type Tags map[string]string or type IDs []int64)
+are handled by the Setter and Changer functions of the unnamed types with the
+same underlying type, so there is no need to provide separate functions for
+each named type.
+
 */
 func (sv *StructVerifier) Verify() error {
+	// Reset warnings and skipped fields of the previous verification
+	sv.warnings = nil
+	sv.skipped = map[string]bool{}
+
+	// Check that fields expected to be shared exist
+	if err := sv.checkSharedKnown(); err != nil {
+		return err
+	}
+
+	// Make the original and reference values
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		return err
+	}
+
+	// Check that setters produce different values for different fields
+	if err := sv.checkVarying(orig); err != nil {
+		return err
+	}
+
+	// Skip fields that cannot be changed in the partial mode
+	if sv.partial {
+		if err := sv.skipUnchangeable(); err != nil {
+			return err
+		}
+	}
+
+	fields := sv.shuffleFields(sv.verifiedFields())
+
+	// Verify fields concurrently if required
+	if sv.parallelism > 1 {
+		err = sv.verifyParallel(fields)
+	} else {
+		// Create clone for each existing field and update the field, check correctness
+		for _, field := range fields {
+			if err = sv.verifyField(orig, ref, field); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Check all fields changed at once if required
+	if sv.allFields {
+		if err := sv.verifyAllFields(orig, ref, sv.changeableFields(fields)); err != nil {
+			return err
+		}
+	}
+
+	// Check values reachable through the accessors
+	if err := sv.verifyAccessors(orig, ref); err != nil {
+		return err
+	}
+
+	// Check nil interface fields are preserved if required
+	if sv.nilIfaces {
+		if err := sv.verifyNilInterfaces(); err != nil {
+			return err
+		}
+	}
+
+	// Check the clone of the nil pointer if required
+	if sv.nilReceiver {
+		if err := sv.verifyNilReceiver(); err != nil {
+			return err
+		}
+	}
+
+	// Check key insertion into maps if required
+	if sv.insertKeys {
+		if err := sv.verifyInsertKeys(); err != nil {
+			return err
+		}
+	}
+
+	// Check nil assignments to pointers of the clone if required
+	if sv.nilPointers {
+		if err := sv.verifyNilPointers(orig, ref, fields); err != nil {
+			return err
+		}
+	}
+
+	// Check slices of the clone do not share arrays if required
+	if sv.crossAlias {
+		if err := sv.verifyCrossAliasing(orig); err != nil {
+			return err
+		}
+	}
+
+	// Check clones of sparse structures if required
+	if sv.sparse {
+		if err := sv.verifySparse(fields); err != nil {
+			return err
+		}
+	}
+
+	// Check clones of empty slices with capacity if required
+	if sv.emptyCap {
+		if err := sv.verifyEmptyCapacity(fields); err != nil {
+			return err
+		}
+	}
+
+	// Check results of registered methods if any
+	if len(sv.methodChecks) != 0 {
+		if err := sv.verifyMethods(orig, sv.changeableFields(fields)); err != nil {
+			return err
+		}
+	}
+
+	// Check both passes of optional fields if registered
+	if len(sv.optionals) != 0 {
+		if err := sv.verifyOptional(fields); err != nil {
+			return err
+		}
+	}
+
+	// Check the cloner produces the same clones if required
+	if sv.deterministic {
+		if err := sv.verifyDeterministic(orig); err != nil {
+			return err
+		}
+	}
+
+	// Check cloners of external types if registered
+	if len(sv.externals) != 0 {
+		if err := sv.verifyExternal(); err != nil {
+			return err
+		}
+	}
+
+	// Check Clone methods of nested structures if required
+	if sv.nestedCloners {
+		if err := sv.verifyNested(); err != nil {
+			return err
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+MustVerify works like [StructVerifier.Verify], but panics on failure, it is
+intended for scripts and examples where checking of the returned error is
+verbose, e.g.:
+
+  clone.NewStructVerifier(newConfig, cloneConfig).MustVerify()
+
+The panic value is the error returned by Verify, so it can be recovered and
+examined by the type, like the errors returned by Verify.
+*/
+func (sv *StructVerifier) MustVerify() {
+	if err := sv.Verify(); err != nil {
+		panic(err)
+	}
+}
+
+// fillOrigRef creates the original and reference values, and checks that they are the same
+func (sv *StructVerifier) fillOrigRef() (any, any, error) {
 	// Make an original value
 	orig, err := sv.autoFill()
 	if err != nil {
-		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+		return nil, nil, &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
 	}
 
 	// And the reference to compare after clone modifications
 	ref, err := sv.autoFill()
 	if err != nil {
-		return &ErrSVRefFill{newErrSV("cannot autofill reference structure: %w", err)}
+		return nil, nil, &ErrSVRefFill{newErrSV("cannot autofill reference structure: %w", err)}
+	}
+
+	// The types of values must be the same, check them first to distinguish type differences from value differences
+	if field, ot, rt, ok := typeMismatch(reflect.ValueOf(orig).Elem(), reflect.ValueOf(ref).Elem(), "",
+		map[uintptr]bool{}); ok {
+		return nil, nil, &ErrSVRefOrigTypeMismatch{
+			structVerifierError:	newErrSV("newly created and filled structures (original and reference)" +
+										" HAVE DIFFERENT TYPES of field %q: orig - %v, ref - %v", field, ot, rt),
+			Field:					field,
+			OrigType:				ot,
+			RefType:				rt,
+		}
 	}
 
 	// They must be the same
-	if !reflect.DeepEqual(orig, ref) {
-		return &ErrSVRefOrigEqual{newErrSV("newly created and filled structures (original and reference)" +
-			" ARE NOT SAME: orig - %#v, ref - %#v", orig, ref)}
+	if !sv.equal(orig, ref) {
+		return nil, nil, &ErrSVRefOrigEqual{newErrSV("newly created and filled structures (original and reference)" +
+			" ARE NOT SAME: orig - %s, ref - %s%s", sv.dump(orig), sv.dump(ref), sv.diffNote(orig, ref, "orig", "ref"))}
 	}
 
-	// Create clone for each existing field and update the field, check correctness
-	for _, field := range structFields(sv.creator()) {
-		// Make a clone
-		clone := sv.cloner(orig)
+	return orig, ref, nil
+}
 
-		// Check that the clone is created correctly - immediately after creation
-		// it should be the same as the original
-		if !reflect.DeepEqual(orig, clone) {
-			return &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
-				" orig - %#v, clone - %#v", orig, clone)}
-		}
+// verifyField creates a clone of orig, updates the field in the clone and checks correctness
+func (sv *StructVerifier) verifyField(orig, ref any, field string) (err error) {
+	// Call hooks if set
+	if sv.onFieldStart != nil {
+		sv.onFieldStart(field)
+	}
+	if sv.onFieldDone != nil {
+		defer func() { sv.onFieldDone(field, err) }()
+	}
 
-		// Update field in the clone
-		if err := sv.autoChange(clone, field); err != nil {
-			return &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field,  err)}
-		}
-	
-		// Compare the original and the reference - they should be the same
-		if !reflect.DeepEqual(orig, ref) {
-			return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%#v) is DIFFERENT from the REFERENCE (%#v)" +
-				" after the CLONE FIELD ----> %q <---- has been CHANGED, clone: %#v", orig, ref, field, clone)}
+	// Fields expected to be shared are checked in the opposite way
+	if sv.isShared(field) {
+		return sv.verifySharedField(orig, field)
+	}
+
+	// Make a clone
+	clone, err := sv.callCloner(orig, field)
+	if err != nil {
+		return err
+	}
+
+	// Check that the clone is created correctly - immediately after creation
+	// it should be the same as the original
+	if !sv.equal(orig, clone) {
+		err := &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
+			" orig - %s, clone - %s%s", sv.dump(orig), sv.dump(clone), sv.diffNote(orig, clone, "orig", "clone"))}
+		if sv.repro {
+			err.repro = sv.reproNotEqual(orig, clone)
 		}
+		return err
+	}
 
-		// Compare the clone and the original structure - they should NOT be the same
-		if reflect.DeepEqual(orig, clone) {
-			return &ErrSVCloneOrigEqual{newErrSV(
-				"CLONE field %q has been UPDATED but the clone is EQUAL the ORIGINAL value: %#v", field, clone)}
+	// Check that the clone does not share pointers with the original
+	if err := sv.checkShared(orig, clone, field); err != nil {
+		return err
+	}
+
+	// Perform user-defined aliasing checks if any
+	if err := sv.runAliasChecks(orig, clone, field); err != nil {
+		return err
+	}
+
+	// Functions cannot be deeply cloned, copying of the function is correct
+	if sv.isFuncField(field) {
+		return nil
+	}
+
+	// Arrays of zero length hold nothing to change
+	if sv.isEmptyArrayField(field) {
+		return nil
+	}
+
+	// Keep the field value before the change to reproduce the failure
+	var before string
+	if sv.repro {
+		before = sv.dump(fieldByPath(reflect.ValueOf(clone).Elem(), field))
+	}
+
+	// Update field in the clone
+	if err := sv.autoChange(clone, field); err != nil {
+		return &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field,  err)}
+	}
+
+	// Compare the original and the reference - they should be the same
+	if !sv.equal(orig, ref) {
+		err := &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+			" at %q after the CLONE FIELD ----> %q <---- has been CHANGED, clone: %s%s",
+			sv.dump(orig), sv.dump(ref), sv.origDiff(orig, ref), field, sv.dump(clone),
+			sv.diffNote(ref, orig, "ref", "orig"))}
+		if sv.repro {
+			err.repro = sv.reproChanged(orig, ref, clone, field, before)
 		}
+		return err
+	}
+
+	// Compare the clone and the original structure - they should NOT be the same
+	if sv.equal(orig, clone) {
+		return &ErrSVCloneOrigEqual{newErrSV(
+			"CLONE field %q has been UPDATED but the clone is EQUAL the ORIGINAL value: %s", field, sv.dump(clone))}
 	}
 
 	// OK
 	return nil
 }
 
+// callCloner calls the cloner function for orig and returns the created clone.
+// If the cloner panics, the panic is converted to the *ErrSVClonePanic error
+// unless the panics propagation is enabled. The type of the created clone must
+// be the same as the type of orig
+func (sv *StructVerifier) callCloner(orig any, field string) (any, error) {
+	clone, err := sv.safeCall(orig, field)
+	if err != nil {
+		return nil, err
+	}
+
+	if ot, ct := reflect.TypeOf(orig), reflect.TypeOf(clone); ot != ct {
+		return nil, &ErrSVCloneTypeMismatch{newErrSV("cloner function returned value of type %v," +
+			" but the original has type %v", ct, ot)}
+	}
+
+	return clone, nil
+}
+
+// safeCall calls the cloner function and recovers its panics if required
+func (sv *StructVerifier) safeCall(orig any, field string) (clone any, err error) {
+	if !sv.propagatePanics {
+		defer func() {
+			if v := recover(); v != nil {
+				err = &ErrSVClonePanic{
+					structVerifierError:	newErrSV("cloner function panicked while processing field %q: %v", field, v),
+					Field:					field,
+					Value:					v,
+				}
+			}
+		}()
+	}
+
+	return sv.cloner(orig), nil
+}
+
+/*
+SampleFilled returns a new instance of the structure filled the same way as the
+original filled by [StructVerifier.Verify], using the configured Setter
+functions, concrete producers and the seed. The verification is not performed.
+It is a quick way to obtain a populated structure to reuse as a test fixture.
+Each call returns a new instance with the same values, the *[ErrSVOrigFill]
+error is returned if the structure cannot be filled.
+*/
+func (sv *StructVerifier) SampleFilled() (any, error) {
+	sample, err := sv.autoFill()
+	if err != nil {
+		return nil, &ErrSVOrigFill{newErrSV("cannot autofill sample structure: %w", err)}
+	}
+
+	return sample, nil
+}
+
 // autoFill automatically creates struct and fills the fields of supported types. It returns
 // interface to the filled structure or an error if structure contains fields of unsupported types
 func (sv *StructVerifier) autoFill() (any, error) {
@@ -336,85 +960,88 @@ func (sv *StructVerifier) autoFill() (any, error) {
 	// Convert inerface to reflect.Value
 	s := reflect.ValueOf(inst).Elem()
 
-	// Create new user defined setters to refresh initial values
-	uSetters := make([]Setter, 0, len(sv.setters))
-	for _, mkSetter := range sv.setters {
-		uSetters = append(uSetters, mkSetter())
-	}
+	// Create filler to set values of all fields
+	fl := sv.newFiller()
 
 	for i := 0; i < s.NumField(); i++ {
 		// Get the i-field
 		f := s.Field(i)
 		name := s.Type().Field(i).Name
 
-		// Filter unexported fields
-		if c := name[0]; c == '_' || (c >= 'a' && c <= 'z') {
-			// Skip this field
+		// Filter unexported fields and locks
+		if !verifiable(s.Type().Field(i)) {
+			// Promoted fields of unexported structures embedded by value are filled one by one
+			if isValueEmbedded(s.Type().Field(i)) {
+				if err := sv.fillPromoted(fl, s, s.Type().Field(i)); err != nil {
+					return nil, err
+				}
+			}
 			continue
 		}
 
-		// Try to set values using user defined and embedded setters
-		for _, setter := range append(uSetters, EmbSetters()...) {
-			if v := setter(f); v != nil {
-				// Set field value to v
-				f.Set(reflect.ValueOf(v))
-				// Go to next field
-				goto nextField
-			}
+		// Skip fields of unsupported types found by the previous fillings
+		if sv.skipped[name] {
+			continue
 		}
 
-		// No suitable setter - unsupported type of field
-		return nil, fmt.Errorf("field %q has unsupported type to set - %q", name, f.Type())
+		// Try to create value using user defined and embedded setters
+		v, err := fl.value(f, name)
+		if err != nil {
+			if sv.partial {
+				// Leave the field with its initial value
+				sv.skipField(name, err)
+				continue
+			}
+			return nil, err
+		}
 
-		nextField:
+		// Set field value to v
+		f.Set(v)
 	}
 
+	// Keep the reached state to be taken by SeedState
+	last := *fl.state
+	sv.lastState = &last
+
 	return inst, nil
 }
 
-// structFields returns a list of field names of the structure specified by si
+// structFields returns a list of fields of the structure specified by si, it is
+// the only enumerator of the verified fields. Unexported fields and locks are
+// excluded, structures embedded by value are replaced by their promoted
+// fields, like "B.Items", see fieldByPath
 func structFields(si any) []string {
 	var fields []string
 
-	s := reflect.ValueOf(si).Elem()
-	for i := 0; i < s.NumField(); i++ {
-		// Filter unexported fields
-		name := s.Type().Field(i).Name
-		if c := name[0]; c == '_' || (c >= 'a' && c <= 'z') {
-			// Skip this field
-			continue
+	t := reflect.TypeOf(si).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if verifiable(f) || isValueEmbedded(f) {
+			fields = append(fields, expandField(f, f.Name)...)
 		}
-		fields = append(fields, name)
 	}
 
 	return fields
 }
 
-// autoFill automatically changed the fields of the structure of supported types.
-// It returns an error if structure contains fields of unsupported types
+// autoChange automatically changes the field of the structure, the field can
+// be a promoted field of the embedded structure, like "B.Items". It returns
+// an error if the field has unsupported type
 func (sv *StructVerifier) autoChange(si any, field string) error {
 	structVal := reflect.ValueOf(si).Elem()
 
-	for i := 0; i < structVal.NumField(); i++ {
-		if structVal.Type().Field(i).Name != field {
-			continue
-		}
-
-		// Get the current struct'structVal field
-		f := structVal.Field(i)
-
-		// Try to change values using user defined and embedded changers
-		for _, changer := range append(sv.changers, EmbChangers()...) {
-			if changer(f) {
-				// Ok, field found and updated
-				return nil
-			}
-		}
+	f := fieldByPath(structVal, field)
+	if !f.IsValid() {
+		return &ErrSVFieldNotFound{newErrSV("field %q was not found in the structure %s",
+			field, sv.dump(structVal.Interface()))}
+	}
 
-		// No suitable setter - unsupported type of field
-		return &ErrSVChange{newErrSV("field %q has unsupported type to change - %q",
-							structVal.Type().Field(i).Name, f.Type())}
+	// Try to change values using user defined and embedded changers
+	if sv.newChanger().change(f) {
+		// Ok, field found and updated
+		return nil
 	}
 
-	return &ErrSVFieldNotFound{newErrSV("field %q was not found in the structure %#v", field, structVal.Interface())}
+	// No suitable setter - unsupported type of field
+	return &ErrSVChange{newErrSV("field %q has unsupported type to change - %q", field, f.Type())}
 }
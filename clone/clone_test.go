@@ -2,8 +2,14 @@ package clone
 
 import (
 	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
 	"testing"
+	"time"
 	"reflect"
+	"strings"
+	"sync"
 	"errors"
 )
 
@@ -60,6 +66,35 @@ func TestRerFillFail(t *testing.T) {
 	}
 }
 
+func TestSampleFilled(t *testing.T) {
+	type testSample struct {
+		Name	string
+		Vals	[]int
+	}
+	sv := NewStructVerifier(func() any { return &testSample{} }, func(x any) any { return x })
+
+	first, err := sv.SampleFilled()
+	if err != nil {
+		t.Fatalf("cannot get filled sample: %v", err)
+	}
+	sample := first.(*testSample)	//nolint:forcetypeassert
+	if sample.Name == "" || len(sample.Vals) == 0 {
+		t.Errorf("sample is not filled: %#v", sample)
+	}
+
+	second, _ := sv.SampleFilled()
+	if first == second || !reflect.DeepEqual(first, second) {
+		t.Errorf("samples must be different instances with the same values: %#v, %#v", first, second)
+	}
+
+	// Channels are not supported by the embedded setters
+	_, err = NewStructVerifier(func() any { return &struct{ Ch chan int }{} }, func(x any) any { return x }).
+		SampleFilled()
+	if !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
 func TestOrigRefEqualFail(t *testing.T) {
 	val := false
 	sv := NewStructVerifier(
@@ -88,6 +123,50 @@ func TestOrigRefEqualFail(t *testing.T) {
 	}
 }
 
+func TestOrigRefTypeMismatch(t *testing.T) {
+	type testNested struct {
+		Vals	[]any
+	}
+	type testDrift struct {
+		Name	string
+		Nested	testNested
+	}
+
+	filled := 0
+	sv := NewStructVerifier(
+		func() any { return &testDrift{} },	// creator function
+		func(x any) any { return x },		// cloner function
+	).AddSetters(func() Setter {
+		return func(v reflect.Value) any {
+			if v.Type() != reflect.TypeOf((*any)(nil)).Elem() {
+				return nil
+			}
+			// The reference gets the value of another type
+			filled++
+			if filled > 1 {
+				return int64(1)
+			}
+			return 1
+		}
+	})
+
+	err := sv.Verify()
+
+	var errMismatch *ErrSVRefOrigTypeMismatch
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because types of the reference values differ")
+	case errors.As(err, &errMismatch):
+		if errMismatch.Field != "Nested.Vals" || errMismatch.OrigType != reflect.TypeOf(0) ||
+			errMismatch.RefType != reflect.TypeOf(int64(0)) {
+			t.Errorf("unexpected mismatch: field %q, orig - %v, ref - %v",
+				errMismatch.Field, errMismatch.OrigType, errMismatch.RefType)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVRefOrigTypeMismatch", err, err)
+	}
+}
+
 func TestCloneEmbedded(t *testing.T) {
 	type complexStruct struct {
 		IntVal		int
@@ -284,3 +363,643 @@ func Test_autoChangeFieldNotFound(t *testing.T) {
 		t.Errorf("got unexpected error %T (%v), want - *ErrSVFieldNotFound", err, err)
 	}
 }
+
+func TestEmbSettersDistinctFields(t *testing.T) {
+	type pairsStruct struct {
+		I1, I2		int
+		L1, L2		int64
+		IS1, IS2	[]int
+		LS1, LS2	[]int64
+		SS1, SS2	[]string
+		M1, M2		map[string]any
+	}
+
+	// The embedded setters are created once per filling, so their
+	// counters produce different values for fields of the same type
+	sv := NewStructVerifier(func() any { return &pairsStruct{} }, func(x any) any { return x })
+
+	filled, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill structure: %v", err)
+	}
+
+	s := reflect.ValueOf(filled).Elem()
+	for i := 0; i < s.NumField(); i += 2 {
+		if a, b := s.Field(i).Interface(), s.Field(i + 1).Interface(); reflect.DeepEqual(a, b) {
+			t.Errorf("fields %q and %q of the same type have the same value: %#v",
+				s.Type().Field(i).Name, s.Type().Field(i + 1).Name, a)
+		}
+	}
+}
+
+func TestCloneRunes(t *testing.T) {
+	type runeStruct struct {
+		R1		rune
+		R2		rune
+		Runes1	[]rune
+		Runes2	[]rune
+	}
+
+	cloner := func(x any) any {
+		orig, ok := x.(*runeStruct)
+		if !ok {
+			panic(fmt.Sprintf("unsupported type to clone - %T, want - *runeStruct", x))
+		}
+
+		rv := *orig
+		rv.Runes1 = make([]rune, len(orig.Runes1))
+		copy(rv.Runes1, orig.Runes1)
+		rv.Runes2 = make([]rune, len(orig.Runes2))
+		copy(rv.Runes2, orig.Runes2)
+
+		return &rv
+	}
+
+	sv := NewStructVerifier(func() any { return &runeStruct{} }, cloner)
+	if err := sv.Verify(); err != nil {
+		t.Errorf("rune structure verification failed: %v", err)
+	}
+
+	// Check that different fields of the same type have different values
+	filled, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill rune structure: %v", err)
+	}
+	//nolint:forcetypeassert // autoFill returns value created by the creator function
+	if rs := filled.(*runeStruct); rs.R1 == rs.R2 || reflect.DeepEqual(rs.Runes1, rs.Runes2) {
+		t.Errorf("fields of the same type have the same values: %#v", rs)
+	}
+
+	// Shallow clone must be detected
+	err = NewStructVerifier(
+		func() any { return &runeStruct{} },
+		func(x any) any { rv := *x.(*runeStruct); return &rv },	//nolint:forcetypeassert
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestClonePanic(t *testing.T) {
+	panicCloner := func(x any) any {
+		panic(fmt.Sprintf("unsupported type to clone - %T", x))
+	}
+
+	err := NewStructVerifier(func() any { return &struct{I int}{} }, panicCloner).Verify()
+
+	var errPanic *ErrSVClonePanic
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because cloner function panics")
+	case errors.As(err, &errPanic):
+		// OK, expected error, check its content
+		if errPanic.Field != "I" || errPanic.Value == nil {
+			t.Errorf("invalid content of ErrSVClonePanic: field - %q, value - %v", errPanic.Field, errPanic.Value)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVClonePanic", err, err)
+	}
+
+	// Panic must be propagated if required
+	defer func() {
+		if recover() == nil {
+			t.Errorf("cloner function panic was not propagated")
+		}
+	}()
+	_ = NewStructVerifier(func() any { return &struct{I int}{} }, panicCloner).PropagatePanics().Verify()
+}
+
+func TestMustVerify(t *testing.T) {
+	type testMust struct {
+		I	int
+		S	[]int
+	}
+
+	sv := NewStructVerifier(
+		func() any { return &testMust{} },
+		func(x any) any {
+			orig := *x.(*testMust)	//nolint:forcetypeassert
+			orig.S = append([]int(nil), orig.S...)
+			return &orig
+		},
+	)
+
+	// Must not panic on success
+	sv.MustVerify()
+}
+
+func TestMustVerifyPanic(t *testing.T) {
+	type testMust struct {
+		I	int
+		S	[]int
+	}
+
+	// Shallow copy shares the slice
+	sv := NewStructVerifier(
+		func() any { return &testMust{} },
+		func(x any) any {
+			orig := *x.(*testMust)	//nolint:forcetypeassert
+			return &orig
+		},
+	)
+
+	defer func() {
+		r := recover()
+		err, _ := r.(error)
+		switch {
+		case r == nil:
+			t.Errorf("MustVerify did not panic on failure")
+		case errors.As(err, new(*ErrSVOrigChanged)):
+			// OK, expected error
+		default:
+			t.Errorf("got unexpected panic value %T (%v), want - *ErrSVOrigChanged", r, r)
+		}
+	}()
+
+	sv.MustVerify()
+}
+
+func TestCloneBig(t *testing.T) {
+	type bigStruct struct {
+		I1, I2	*big.Int
+		R		*big.Rat
+	}
+
+	cloner := func(shallow bool) ClonerFunc {
+		return func(x any) any {
+			orig, ok := x.(*bigStruct)
+			if !ok {
+				panic(fmt.Sprintf("unsupported type to clone - %T, want - *bigStruct", x))
+			}
+
+			rv := *orig
+			if !shallow {
+				rv.I1 = new(big.Int).Set(orig.I1)
+				rv.I2 = new(big.Int).Set(orig.I2)
+				rv.R = new(big.Rat).Set(orig.R)
+			}
+
+			return &rv
+		}
+	}
+
+	sv := NewStructVerifier(func() any { return &bigStruct{} }, cloner(false)).StrictSetters()
+	if err := sv.Verify(); err != nil {
+		t.Errorf("big numbers structure verification failed: %v", err)
+	}
+
+	err := NewStructVerifier(func() any { return &bigStruct{} }, cloner(true)).Verify()
+	if !errors.As(err, new(*ErrSVSharedPointer)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestClearSettersChangers(t *testing.T) {
+	boolSetter := func() Setter {
+		return func(v reflect.Value) any {
+			if _, ok := v.Interface().(bool); ok {
+				return true
+			}
+			return nil
+		}
+	}
+	boolChanger := func(v reflect.Value) bool {
+		if b, ok := v.Interface().(bool); ok {
+			v.SetBool(!b)
+			return true
+		}
+		return false
+	}
+
+	sv := NewStructVerifier(
+		func() any { return &struct{B bool}{} },
+		func(x any) any { rv := *x.(*struct{B bool}); return &rv },	//nolint:forcetypeassert
+	).AddSetters(boolSetter).AddChangers(boolChanger)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with user-defined handlers failed: %v", err)
+	}
+
+	// Without changers bool field cannot be changed
+	if err := sv.ClearChangers().Verify(); !errors.As(err, new(*ErrSVChange)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVChange", err, err)
+	}
+
+	// Without setters bool field cannot be filled
+	if err := sv.AddChangers(boolChanger).ClearSetters().Verify(); !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
+func TestUnicodeFieldNames(t *testing.T) {
+	type unicodeStruct struct {
+		Ünicode		[]int
+		ünicode		[]int	//nolint:unused	// required for testing
+		Σ			int64
+	}
+
+	if fields, want := structFields(&unicodeStruct{}), []string{"Ünicode", "Σ"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("structFields returned %q, want - %q", fields, want)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &unicodeStruct{} },
+		// Shallow copy, slices are shared
+		func(x any) any { rv := *x.(*unicodeStruct); return &rv },	//nolint:forcetypeassert
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestFieldHooks(t *testing.T) {
+	var started, done []string
+	var failed string
+
+	err := NewStructVerifier(
+		func() any { return &struct{I int; S []string}{} },
+		func(x any) any { rv := *x.(*struct{I int; S []string}); return &rv },	//nolint:forcetypeassert
+	).OnFieldStart(func(field string) {
+		started = append(started, field)
+	}).OnFieldDone(func(field string, err error) {
+		done = append(done, field)
+		if err != nil {
+			failed = field
+		}
+	}).Verify()
+
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	want := []string{"I", "S"}
+	if !reflect.DeepEqual(started, want) || !reflect.DeepEqual(done, want) {
+		t.Errorf("hooks were called for fields: started - %q, done - %q, want - %q", started, done, want)
+	}
+	if failed != "S" {
+		t.Errorf("OnFieldDone reported failure for field %q, want - %q", failed, "S")
+	}
+}
+
+func TestCloneDurations(t *testing.T) {
+	type durStruct struct {
+		Timeout		time.Duration
+		Interval	time.Duration
+		Backoff		[]time.Duration
+	}
+
+	sv := NewStructVerifier(
+		func() any { return &durStruct{} },
+		func(x any) any {
+			orig := x.(*durStruct)	//nolint:forcetypeassert
+			rv := *orig
+			rv.Backoff = make([]time.Duration, len(orig.Backoff))
+			copy(rv.Backoff, orig.Backoff)
+			return &rv
+		},
+	).StrictSetters()
+	if err := sv.Verify(); err != nil {
+		t.Errorf("durations structure verification failed: %v", err)
+	}
+
+	// Durations must be filled by the dedicated setter, not by int64 setter
+	filled, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill durations structure: %v", err)
+	}
+	if ds := filled.(*durStruct); ds.Timeout < time.Second {	//nolint:forcetypeassert
+		t.Errorf("duration field filled by not meaningful value - %v", ds.Timeout)
+	}
+}
+
+func TestCloneTypeMismatch(t *testing.T) {
+	type origStruct struct { I int }
+	type otherStruct struct { I int }
+
+	err := NewStructVerifier(
+		func() any { return &origStruct{} },
+		func(x any) any { return &otherStruct{I: x.(*origStruct).I} },	//nolint:forcetypeassert
+	).Verify()
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone has a different type")
+	case errors.As(err, new(*ErrSVCloneTypeMismatch)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneTypeMismatch", err, err)
+	}
+}
+
+func TestCloneNetAddrs(t *testing.T) {
+	type netStruct struct {
+		Listen	net.IP
+		Peers	[]net.IP
+		Gateway	netip.Addr
+	}
+
+	if err := NewStructVerifier(
+		func() any { return &netStruct{} },
+		func(x any) any {
+			orig := x.(*netStruct)	//nolint:forcetypeassert
+			rv := *orig
+			rv.Listen = append(net.IP(nil), orig.Listen...)
+			rv.Peers = make([]net.IP, 0, len(orig.Peers))
+			for _, ip := range orig.Peers {
+				rv.Peers = append(rv.Peers, append(net.IP(nil), ip...))
+			}
+			return &rv
+		},
+	).StrictSetters().Verify(); err != nil {
+		t.Errorf("network addresses structure verification failed: %v", err)
+	}
+
+	// The address bytes are shared, the change of the clone must be detected
+	err := NewStructVerifier(
+		func() any { return &netStruct{} },
+		func(x any) any {
+			rv := *x.(*netStruct)	//nolint:forcetypeassert
+			return &rv
+		},
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestMaxErrorLen(t *testing.T) {
+	type bigStruct struct {
+		Vals	[]int
+	}
+
+	creator := func() any { return &bigStruct{Vals: make([]int, 0, 1)} }
+	// Shallow copy, the slice is shared with the original
+	cloner := func(x any) any {
+		rv := *x.(*bigStruct)	//nolint:forcetypeassert
+		return &rv
+	}
+	// Produce a large slice to get a long error message
+	setter := func() Setter {
+		return func(v reflect.Value) any {
+			if _, ok := v.Interface().([]int); !ok {
+				return nil
+			}
+			return make([]int, 10000)
+		}
+	}
+	changer := func(v reflect.Value) bool {
+		is, ok := v.Interface().([]int)
+		if !ok {
+			return false
+		}
+		is[len(is)-1]++
+		return true
+	}
+
+	const maxLen = 100
+	err := NewStructVerifier(creator, cloner).AddSetters(setter).AddChangers(changer).
+		SetMaxErrorLen(maxLen).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, truncatedMark) || !strings.Contains(msg, `"Vals"`) {
+		t.Errorf("error message is not truncated or does not contain the field name: %s", msg)
+	}
+	// Three values - original, reference and clone plus the message text
+	if len(msg) > 3 * (maxLen + len(truncatedMark)) + 200 {
+		t.Errorf("error message is too long - %d bytes", len(msg))
+	}
+
+	// No truncation if disabled
+	err = NewStructVerifier(creator, cloner).AddSetters(setter).AddChangers(changer).
+		SetMaxErrorLen(0).Verify()
+	if err == nil || strings.Contains(err.Error(), truncatedMark) {
+		t.Errorf("error message must not be truncated: %v", err)
+	}
+}
+
+func TestCloneStringSets(t *testing.T) {
+	type setStruct struct {
+		Allowed	map[string]struct{}
+		Denied	map[string]struct{}
+	}
+
+	cloneSets := func(x any, shareDenied bool) any {
+		orig := x.(*setStruct)	//nolint:forcetypeassert
+		rv := *orig
+		rv.Allowed = make(map[string]struct{}, len(orig.Allowed))
+		for k := range orig.Allowed {
+			rv.Allowed[k] = struct{}{}
+		}
+		if !shareDenied {
+			rv.Denied = make(map[string]struct{}, len(orig.Denied))
+			for k := range orig.Denied {
+				rv.Denied[k] = struct{}{}
+			}
+		}
+		return &rv
+	}
+
+	if err := NewStructVerifier(
+		func() any { return &setStruct{} },
+		func(x any) any { return cloneSets(x, false) },
+	).StrictSetters().Verify(); err != nil {
+		t.Errorf("sets structure verification failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &setStruct{} },
+		func(x any) any { return cloneSets(x, true) },
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestSetterTypeMismatch(t *testing.T) {
+	type int64Struct struct {
+		N	int64
+	}
+
+	err := NewStructVerifier(
+		func() any { return &int64Struct{} },
+		func(x any) any { rv := *x.(*int64Struct); return &rv },	//nolint:forcetypeassert
+	).AddSetters(func() Setter {
+		return func(v reflect.Value) any {
+			if _, ok := v.Interface().(int64); !ok {
+				return nil
+			}
+			// Wrong type of the value
+			return 1
+		}
+	}).Verify()
+
+	if !errors.As(err, new(*ErrSVSetterTypeMismatch)) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVSetterTypeMismatch", err, err)
+	}
+	if !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, `"N"`) || !strings.Contains(msg, `"int64"`) {
+		t.Errorf("error message does not contain field name or field type: %s", msg)
+	}
+}
+
+type testGuardedStruct struct {
+	sync.Mutex
+	Names	[]string
+	Counts	map[string]any
+	Cache	sync.RWMutex
+}
+
+func (g *testGuardedStruct) Clone() *testGuardedStruct {
+	g.Lock()
+	defer g.Unlock()
+
+	rv := &testGuardedStruct{
+		Names:	append([]string(nil), g.Names...),
+		Counts:	make(map[string]any, len(g.Counts)),
+	}
+	for k, v := range g.Counts {
+		rv.Counts[k] = v
+	}
+
+	return rv
+}
+
+func TestCloneWithMutex(t *testing.T) {
+	var verified []string
+
+	if err := NewStructVerifier(
+		func() any { return &testGuardedStruct{} },
+		func(x any) any { return x.(*testGuardedStruct).Clone() },	//nolint:forcetypeassert
+	).OnFieldStart(func(field string) {
+		verified = append(verified, field)
+	}).Verify(); err != nil {
+		t.Errorf("verification of structure with mutexes failed: %v", err)
+	}
+
+	if want := []string{"Names", "Counts"}; !reflect.DeepEqual(verified, want) {
+		t.Errorf("got verified fields %v, want - %v", verified, want)
+	}
+}
+
+func TestCloneByteRows(t *testing.T) {
+	type rowsStruct struct {
+		Sig		[]byte
+		Rows	[][]byte
+	}
+
+	// cloneRows copies the outer slice, if shareRows is true, inner buffers are shared
+	cloneRows := func(x any, shareRows bool) any {
+		orig := x.(*rowsStruct)	//nolint:forcetypeassert
+		rv := *orig
+		rv.Sig = append([]byte(nil), orig.Sig...)
+		rv.Rows = make([][]byte, 0, len(orig.Rows))
+		for _, row := range orig.Rows {
+			if !shareRows {
+				row = append([]byte(nil), row...)
+			}
+			rv.Rows = append(rv.Rows, row)
+		}
+		return &rv
+	}
+
+	if err := NewStructVerifier(
+		func() any { return &rowsStruct{} },
+		func(x any) any { return cloneRows(x, false) },
+	).StrictSetters().Verify(); err != nil {
+		t.Errorf("byte rows structure verification failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &rowsStruct{} },
+		func(x any) any { return cloneRows(x, true) },
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+	if !strings.Contains(err.Error(), `at "Rows[2][5]"`) {
+		t.Errorf("error does not contain the path to the changed byte: %v", err)
+	}
+}
+
+func TestSettersWithField(t *testing.T) {
+	type serverConf struct {
+		Port	int
+		Workers	int
+	}
+	type appConf struct {
+		Server	serverConf
+		Retries	int
+	}
+
+	// portSetter fills only port fields by valid port numbers
+	portSetter := func() SetterWithField {
+		port := 8080
+		return func(name string, v reflect.Value) any {
+			if _, ok := v.Interface().(int); !ok || !strings.HasSuffix(name, ".Port") {
+				return nil
+			}
+			port++
+			return port
+		}
+	}
+
+	sv := NewStructVerifier(
+		func() any { return &appConf{} },
+		func(x any) any { rv := *x.(*appConf); return &rv },	//nolint:forcetypeassert
+	).AddSettersWithField(portSetter)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with field-aware setters failed: %v", err)
+	}
+
+	filled, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill: %v", err)
+	}
+	conf := filled.(*appConf)	//nolint:forcetypeassert
+	if conf.Server.Port != 8081 || conf.Server.Workers == 8081 || conf.Retries > 8080 {
+		t.Errorf("fields filled incorrectly: %#v", conf)
+	}
+}
+
+func TestSettersWithFieldPaths(t *testing.T) {
+	type elemsConf struct {
+		Ports	[2]int
+		Limits	map[string]int
+		Timeout	*int
+	}
+
+	// Names passed to the setter for int and string values
+	names := map[string]bool{}
+	sv := NewStructVerifier(
+		func() any { return &elemsConf{} },
+		func(x any) any { rv := *x.(*elemsConf); return &rv },	//nolint:forcetypeassert
+	).AddSettersWithField(func() SetterWithField {
+		return func(name string, v reflect.Value) any {
+			if v.Kind() == reflect.Int || v.Kind() == reflect.String {
+				names[name] = true
+			}
+			return nil
+		}
+	})
+
+	if _, err := sv.autoFill(); err != nil {
+		t.Fatalf("cannot autofill: %v", err)
+	}
+	for _, want := range []string{"Ports[0]", "Limits(key #0)", "Timeout"} {
+		if !names[want] {
+			t.Errorf("setter was not called with the name %q, got names: %v", want, names)
+		}
+	}
+	for name := range names {
+		if strings.HasPrefix(name, "Limits[") {
+			return
+		}
+	}
+	t.Errorf("setter was not called for values of the map, got names: %v", names)
+}
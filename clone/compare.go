@@ -0,0 +1,416 @@
+package clone
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// comparator compares the original, reference and cloned values during verification
+type comparator struct {
+	nilEmptyEqual	bool	// nil and empty slices/maps are equal
+	nanEqual		bool	// NaN float values are equal to each other
+	typeEqual		map[reflect.Type]func(a, b any) bool	// user-defined comparators of types
+	fieldEqual		map[string]func(a, b any) bool			// user-defined comparators of fields
+}
+
+// visit is used to detect cycles during the comparison of values
+type visit struct {
+	a, b	uintptr
+	typ		reflect.Type
+}
+
+/*
+Differ is the interface of external comparison libraries, it allows to use
+them instead of the default comparison, see [StructVerifier.UseDiffer]. The
+Equal method reports whether a and b are equal, the Diff method returns a
+human-readable report of differences between a and b.
+*/
+type Differ interface {
+	Equal(a, b any) bool
+	Diff(a, b any) string
+}
+
+/*
+TreatNilAndEmptyEqual makes the verifier treat nil and empty slices and maps as
+equal values. By default, the values are compared by [reflect.DeepEqual] that
+distinguishes nil and empty slices and maps. Therefore, a Clone method that
+deliberately replaces nil slices or maps by empty ones produces the
+*[ErrSVCloneOrigNotEqual] error, use TreatNilAndEmptyEqual if such behavior is
+correct for your code.
+*/
+func (sv *StructVerifier) TreatNilAndEmptyEqual() *StructVerifier {
+	sv.cmp.nilEmptyEqual = true
+	return sv
+}
+
+/*
+FillNonFiniteFloats makes the embedded Setter functions fill float64 and float32
+fields with non-finite values: NaN, +Inf and -Inf in turn, instead of finite
+values used by default. It is useful to check that the cloner handles such
+values properly. The Changer functions replace non-finite values by finite ones.
+
+Note that NaN is not equal to any value, including itself, so [reflect.DeepEqual]
+reports the structures containing NaN as different even if they are copies of
+each other. Therefore, FillNonFiniteFloats also makes the verifier treat NaN
+values as equal to each other, otherwise the original and the reference could
+never be equal. Float values produced by user-defined Setter functions are not
+affected, but they are compared the same way.
+*/
+func (sv *StructVerifier) FillNonFiniteFloats() *StructVerifier {
+	sv.nonFinite = true
+	sv.cmp.nanEqual = true
+	return sv
+}
+
+/*
+RegisterComparator registers the user-defined function eq to compare values of
+type t instead of the default comparison. The eq function is called for the
+values of type t at any level of nesting, including the fields of structures,
+elements of slices and values of maps, but not for unexported fields. It is
+useful for the values that must be compared with some tolerance, e.g. floats
+computed by user-defined Setter functions:
+
+  sv.RegisterComparator(reflect.TypeOf(float64(0)), func(a, b any) bool {
+      return math.Abs(a.(float64) - b.(float64)) < 1e-9
+  })
+
+Registering the comparator for the same type again replaces the previous one.
+The values of other types are compared exactly, as before.
+*/
+func (sv *StructVerifier) RegisterComparator(t reflect.Type, eq func(a, b any) bool) *StructVerifier {
+	if sv.cmp.typeEqual == nil {
+		sv.cmp.typeEqual = map[reflect.Type]func(a, b any) bool{}
+	}
+	sv.cmp.typeEqual[t] = eq
+
+	return sv
+}
+
+/*
+RegisterFieldComparator registers the user-defined function eq to compare the
+values of the field instead of the default comparison. Unlike the comparators
+registered by [StructVerifier.RegisterComparator], it affects only the single
+field, so the comparison of other fields of the same type is not weakened. It
+is useful for fields that break the deep comparison, e.g. time.Time with the
+monotonic clock reading or lazily computed caches:
+
+  sv.RegisterFieldComparator("Created", func(a, b any) bool {
+      return a.(time.Time).Equal(b.(time.Time))
+  })
+
+The field is identified by its path from the verified structure, e.g.
+"Server.Started" for the field of the nested structure. Fields of structures
+stored in slices, maps and pointers get the path of the field that contains
+them, e.g. "Items.Created" for the Created field of elements of the Items
+slice. Only exported fields can be compared this way. The structures are
+compared field by field and eq is called for the values of the field, it takes
+precedence over the comparators of types. Registering the comparator for the
+same field again replaces the previous one.
+*/
+func (sv *StructVerifier) RegisterFieldComparator(field string, eq func(a, b any) bool) *StructVerifier {
+	if sv.cmp.fieldEqual == nil {
+		sv.cmp.fieldEqual = map[string]func(a, b any) bool{}
+	}
+	sv.cmp.fieldEqual[field] = eq
+
+	return sv
+}
+
+/*
+UseDiffer makes the verifier compare values by the Differ d instead of
+[reflect.DeepEqual] and the comparison settings, such as
+[StructVerifier.TreatNilAndEmptyEqual] and [StructVerifier.RegisterComparator].
+The differences reported by d are included in the messages of the
+*[ErrSVOrigChanged], *[ErrSVCloneOrigNotEqual] and *[ErrSVRefOrigEqual] errors,
+that is much more readable than dumps of whole values.
+
+This package does not depend on any comparison library, so the Differ should
+be implemented by the user, e.g. for github.com/google/go-cmp/cmp:
+
+  type cmpDiffer struct {
+      opts []cmp.Option
+  }
+
+  func (d cmpDiffer) Equal(a, b any) bool  { return cmp.Equal(a, b, d.opts...) }
+  func (d cmpDiffer) Diff(a, b any) string { return cmp.Diff(a, b, d.opts...) }
+
+  sv.UseDiffer(cmpDiffer{})
+
+Passing nil restores the default comparison.
+*/
+func (sv *StructVerifier) UseDiffer(d Differ) *StructVerifier {
+	sv.differ = d
+	return sv
+}
+
+// equal reports whether a and b are deeply equal according to the verifier settings
+func (sv *StructVerifier) equal(a, b any) bool {
+	if sv.differ != nil {
+		return sv.differ.Equal(a, b)
+	}
+
+	if t := reflect.TypeOf(a); !sv.cmp.custom() && !hasAtomic(t) && !hasSyncMap(t) && !hasFunc(t) {
+		// Use standard comparison
+		return reflect.DeepEqual(a, b)
+	}
+
+	cmp := sv.cmp
+	if len(cmp.fieldEqual) != 0 && reflect.TypeOf(a) != reflect.TypeOf(sv.creator()) {
+		// Paths of fields are known only for the verified structures
+		cmp.fieldEqual = nil
+	}
+
+	return cmp.deepEqual(reflect.ValueOf(a), reflect.ValueOf(b), map[visit]bool{})
+}
+
+// diffNote returns the differences of a and b named aName and bName reported by
+// the Differ to be appended to the error message, or an empty string if it is not used
+func (sv *StructVerifier) diffNote(a, b any, aName, bName string) string {
+	if sv.differ == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(", diff (-%s +%s):\n%s", aName, bName, sv.differ.Diff(a, b))
+}
+
+// equalField reports whether the values a and b of the field of the verified
+// structure at path are equal according to the verifier settings, the
+// user-defined comparators of the field and of its nested fields are applied
+func (sv *StructVerifier) equalField(a, b reflect.Value, path string) bool {
+	if sv.differ != nil {
+		return sv.differ.Equal(a.Interface(), b.Interface())
+	}
+
+	if eq, ok := sv.cmp.fieldEqual[path]; ok {
+		return eq(a.Interface(), b.Interface())
+	}
+
+	return sv.cmp.deepEqualAt(a, b, path, map[visit]bool{})
+}
+
+// origDiff returns the path to the first difference of the original and the
+// reference structures, see diffPath
+func (sv *StructVerifier) origDiff(orig, ref any) string {
+	return sv.diffPath(reflect.ValueOf(orig).Elem(), reflect.ValueOf(ref).Elem(), "", 0)
+}
+
+// diffPath returns the path to the first exported element of a and b which
+// values differ, e.g. "Rows[2][3]" or "Conf.Limits[key]". If the difference
+// cannot be localized deeper, the path to a and b itself is returned
+func (sv *StructVerifier) diffPath(a, b reflect.Value, path string, depth int) string {
+	if depth > sv.maxDepth || a.Type() != b.Type() {
+		return path
+	}
+
+	differ := func(x, y reflect.Value) bool {
+		return !x.CanInterface() || !sv.equal(x.Interface(), y.Interface())
+	}
+
+	switch a.Kind() { //nolint:exhaustive	// other kinds cannot be localized deeper
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return path
+		}
+		return sv.diffPath(a.Elem(), b.Elem(), path, depth + 1)
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if !verifiable(f) {
+				continue
+			}
+
+			fPath := fieldPath(path, f.Name)
+			if eq, ok := sv.cmp.fieldEqual[fPath]; ok && sv.differ == nil {
+				// The field is compared by the user-defined comparator only
+				if !eq(a.Field(i).Interface(), b.Field(i).Interface()) {
+					return fPath
+				}
+				continue
+			}
+
+			if differ(a.Field(i), b.Field(i)) {
+				return sv.diffPath(a.Field(i), b.Field(i), fPath, depth + 1)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return path
+		}
+		for i := 0; i < a.Len(); i++ {
+			if differ(a.Index(i), b.Index(i)) {
+				return sv.diffPath(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), depth + 1)
+			}
+		}
+
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return path
+		}
+		for _, key := range sortedKeys(a) {
+			bv := b.MapIndex(key)
+			if !bv.IsValid() {
+				return path
+			}
+			if differ(a.MapIndex(key), bv) {
+				return sv.diffPath(a.MapIndex(key), bv, fmt.Sprintf("%s[%v]", path, key), depth + 1)
+			}
+		}
+	}
+
+	return path
+}
+
+// custom returns true if the comparison differs from reflect.DeepEqual
+func (c *comparator) custom() bool {
+	return c.nilEmptyEqual || c.nanEqual || len(c.typeEqual) != 0 || len(c.fieldEqual) != 0
+}
+
+// deepEqual works like reflect.DeepEqual, but takes into account the comparator settings
+func (c *comparator) deepEqual(a, b reflect.Value, visited map[visit]bool) bool {
+	return c.deepEqualAt(a, b, "", visited)
+}
+
+// fieldPath returns the path of the field name of the structure at path
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+//nolint:cyclop,gocyclo,exhaustive	// it is a type switch by kind, the rest of kinds are handled by default
+// deepEqualAt works like deepEqual, path is the path of a and b from the root
+// structure used to find the user-defined comparators of fields
+func (c *comparator) deepEqualAt(a, b reflect.Value, path string, visited map[visit]bool) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	// Use the user-defined comparator if registered
+	if eq, ok := c.typeEqual[a.Type()]; ok && a.CanInterface() {
+		return eq(a.Interface(), b.Interface())
+	}
+
+	// Check reference types for nil values, identity and cycles
+	switch a.Kind() {
+	case reflect.Map, reflect.Slice:
+		if c.nilEmptyEqual && a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+		fallthrough
+	case reflect.Pointer:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Pointer() == b.Pointer() && (a.Kind() != reflect.Slice || a.Len() == b.Len()) {
+			return true
+		}
+
+		v := visit{a.Pointer(), b.Pointer(), a.Type()}
+		if visited[v] {
+			return true
+		}
+		visited[v] = true
+	}
+
+	switch a.Kind() {
+	case reflect.Array, reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !c.deepEqualAt(a.Index(i), b.Index(i), path, visited) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return c.deepEqualAt(a.Elem(), b.Elem(), path, visited)
+
+	case reflect.Pointer:
+		return c.deepEqualAt(a.Elem(), b.Elem(), path, visited)
+
+	case reflect.Struct:
+		// Compare values stored in atomic wrappers
+		if av, ok := atomicLoad(a); ok {
+			bv, _ := atomicLoad(b)
+			return c.deepEqualAt(av, bv, path, visited)
+		}
+
+		// Compare entries of sync.Map values
+		if am, ok := syncMapSnapshot(a); ok {
+			bm, _ := syncMapSnapshot(b)
+			return c.deepEqualAt(am, bm, path, visited)
+		}
+
+		for i := 0; i < a.NumField(); i++ {
+			fPath := fieldPath(path, a.Type().Field(i).Name)
+			if eq, ok := c.fieldEqual[fPath]; ok && a.Field(i).CanInterface() {
+				// Use the user-defined comparator of the field
+				if !eq(a.Field(i).Interface(), b.Field(i).Interface()) {
+					return false
+				}
+				continue
+			}
+
+			if !c.deepEqualAt(a.Field(i), b.Field(i), fPath, visited) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			bv := b.MapIndex(k)
+			if !bv.IsValid() || !c.deepEqualAt(a.MapIndex(k), bv, path, visited) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Func:
+		// Functions are equal if they are copies of the same function
+		return funcEqual(a, b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		if c.nanEqual && math.IsNaN(a.Float()) && math.IsNaN(b.Float()) {
+			return true
+		}
+		return a.Float() == b.Float()
+
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+
+	case reflect.String:
+		return a.String() == b.String()
+
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,324 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComparatorDeepEqual(t *testing.T) {
+	type node struct {
+		Next	*node
+		Vals	[]int
+		hidden	map[string]int
+	}
+
+	cyclic1, cyclic2 := &node{Vals: []int{1}}, &node{Vals: []int{1}}
+	cyclic1.Next, cyclic2.Next = cyclic1, cyclic2
+
+	tests := []struct {
+		a, b		any
+		strict		bool	// result of the strict comparison
+		nilEmpty	bool	// result of the comparison with nil == empty
+	}{
+		{[]int(nil), []int{}, false, true},
+		{map[string]int(nil), map[string]int{}, false, true},
+		{[]int{1, 2}, []int{1, 2}, true, true},
+		{[]int{1, 2}, []int{1, 3}, false, false},
+		{&node{hidden: map[string]int{}}, &node{}, false, true},
+		{&node{Vals: []int{}}, &node{hidden: map[string]int{"a": 1}}, false, false},
+		{cyclic1, cyclic2, true, true},
+		{[]any{nil, "a"}, []any{nil, "a"}, true, true},
+		{[]any{1}, []any{int64(1)}, false, false},
+	}
+
+	for i, test := range tests {
+		strict := &comparator{}
+		nilEmpty := &comparator{nilEmptyEqual: true}
+
+		if got := strict.deepEqual(reflect.ValueOf(test.a), reflect.ValueOf(test.b), map[visit]bool{}); got != test.strict {
+			t.Errorf("[%d] strict comparison of %#v and %#v returned %t, want - %t", i, test.a, test.b, got, test.strict)
+		}
+		if got := nilEmpty.deepEqual(reflect.ValueOf(test.a), reflect.ValueOf(test.b), map[visit]bool{}); got != test.nilEmpty {
+			t.Errorf("[%d] nil/empty comparison of %#v and %#v returned %t, want - %t",
+				i, test.a, test.b, got, test.nilEmpty)
+		}
+	}
+}
+
+type testNilSlices struct {
+	I	int
+	S	[]string
+}
+
+// cloneNilSlices returns a clone of testNilSlices that replaces nil slice by empty slice
+func cloneNilSlices(x any) any {
+	orig := x.(*testNilSlices)	//nolint:forcetypeassert
+	rv := *orig
+	rv.S = make([]string, len(orig.S))
+	copy(rv.S, orig.S)
+	return &rv
+}
+
+func nilSliceSetter() Setter {
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().([]string); ok {
+			return []string(nil)
+		}
+		return nil
+	}
+}
+
+func stringAppendChanger(v reflect.Value) bool {
+	if ss, ok := v.Interface().([]string); ok {
+		v.Set(reflect.ValueOf(append(ss, "new")))
+		return true
+	}
+	return false
+}
+
+func TestTreatNilAndEmptyEqual(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testNilSlices{} }, cloneNilSlices).
+		AddSetters(nilSliceSetter).AddChangers(stringAppendChanger).
+		TreatNilAndEmptyEqual()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with nil and empty equality failed: %v", err)
+	}
+}
+
+func TestTreatNilAndEmptyNotEqual(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testNilSlices{} }, cloneNilSlices).
+		AddSetters(nilSliceSetter).AddChangers(stringAppendChanger)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone replaces nil slice by empty slice")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+}
+
+type testFloatRatios struct {
+	Ratio	float64
+	Ratios	[]float64
+}
+
+func cloneFloatRatios(x any) any {
+	rv := *x.(*testFloatRatios)	//nolint:forcetypeassert
+	rv.Ratios = append([]float64(nil), rv.Ratios...)
+	return &rv
+}
+
+// newFloatSetter returns a setter whose filling passes compute the same values
+// in a different way, each verifier requires its own setter
+func newFloatSetter() func() Setter {
+	// Variables are used to prevent exact computation of constants
+	pass := 0
+	x1, x2 := 0.1, 0.2
+	return func() Setter {
+		pass++
+		first := pass % 2 == 1
+		return func(v reflect.Value) any {
+			switch v.Interface().(type) {
+			case float64:
+				if first {
+					return x1 + x2
+				}
+				return 0.3
+			case []float64:
+				return []float64{x1 * 3, 0.3}
+			default:
+				return nil
+			}
+		}
+	}
+}
+
+func floatChanger(v reflect.Value) bool {
+	switch x := v.Interface().(type) {
+	case float64:
+		v.SetFloat(x * 2)
+	case []float64:
+		x[len(x)-1]++
+	default:
+		return false
+	}
+	return true
+}
+
+func TestRegisterComparator(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testFloatRatios{} },
+		cloneFloatRatios,
+		WithSetters(newFloatSetter()),
+		WithChangers(floatChanger),
+		WithComparator(func(a, b float64) bool {
+			return math.Abs(a - b) < 1e-9
+		}),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with float comparator failed: %v", err)
+	}
+}
+
+func TestRegisterComparatorMissing(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testFloatRatios{} },
+		cloneFloatRatios,
+		WithSetters(newFloatSetter()),
+		WithChangers(floatChanger),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because float values are compared exactly")
+	case errors.As(err, new(*ErrSVRefOrigEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVRefOrigEqual", err, err)
+	}
+}
+
+type testScoreStats struct {
+	Score	float64
+}
+
+type testScored struct {
+	Score	float64
+	Weight	float64
+	Stats	*testScoreStats
+}
+
+// cloneScored returns a clone of testScored with the scores recomputed with an
+// error, the weight is recomputed too if approxWeight is set
+func cloneScored(x any, approxWeight bool) any {
+	orig := x.(*testScored)	//nolint:forcetypeassert
+	rv := &testScored{Score: orig.Score + 1e-12, Weight: orig.Weight}
+	if approxWeight {
+		rv.Weight += 1e-12
+	}
+	if orig.Stats != nil {
+		rv.Stats = &testScoreStats{Score: orig.Stats.Score + 1e-12}
+	}
+
+	return rv
+}
+
+// approxFloat compares float64 values with the tolerance
+func approxFloat(a, b any) bool {
+	return math.Abs(a.(float64) - b.(float64)) < 1e-9	//nolint:forcetypeassert
+}
+
+func TestRegisterFieldComparator(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testScored{} },
+		func(x any) any { return cloneScored(x, false) },
+		WithFieldComparator("Score", approxFloat),
+		WithFieldComparator("Stats.Score", approxFloat),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with field comparators failed: %v", err)
+	}
+}
+
+func TestRegisterFieldComparatorMissing(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testScored{} },
+		func(x any) any { return cloneScored(x, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because scores are compared exactly")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+}
+
+func TestRegisterFieldComparatorOtherFields(t *testing.T) {
+	// Other fields of the same type are compared exactly
+	sv := NewStructVerifierWith(
+		func() any { return &testScored{} },
+		func(x any) any { return cloneScored(x, true) },
+		WithFieldComparator("Score", approxFloat),
+		WithFieldComparator("Stats.Score", approxFloat),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the weight is compared exactly")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+
+	orig, _ := sv.SampleFilled()
+	if path := sv.origDiff(orig, sv.cloner(orig)); path != "Weight" {
+		t.Errorf("got difference at %q, want - %q", path, "Weight")
+	}
+}
+
+// testDiffer compares values by reflect.DeepEqual and reports the
+// differing exported fields of structures, it counts the comparisons
+type testDiffer struct {
+	calls	*int
+}
+
+func (d testDiffer) Equal(a, b any) bool {
+	*d.calls++
+	return reflect.DeepEqual(a, b)
+}
+
+func (d testDiffer) Diff(a, b any) string {
+	av, bv := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	var diff []string
+	for i := 0; i < av.NumField(); i++ {
+		if x, y := av.Field(i).Interface(), bv.Field(i).Interface(); !reflect.DeepEqual(x, y) {
+			diff = append(diff, fmt.Sprintf("%s: -%v +%v", av.Type().Field(i).Name, x, y))
+		}
+	}
+
+	return strings.Join(diff, "\n")
+}
+
+func TestUseDiffer(t *testing.T) {
+	type testStruct struct {
+		Name	string
+		Vals	[]int
+	}
+	shareVals := func(x any) any {
+		rv := *x.(*testStruct)	//nolint:forcetypeassert
+		return &rv
+	}
+
+	calls := 0
+	err := NewStructVerifierWith(func() any { return &testStruct{} }, shareVals,
+		WithDiffer(testDiffer{&calls}),
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	if calls == 0 {
+		t.Errorf("the differ has not been used for comparison")
+	}
+	if want := "diff (-ref +orig):\nVals: -[1 2] +[1 4]"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error message %q does not contain the differ report %q", err, want)
+	}
+}
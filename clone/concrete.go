@@ -0,0 +1,211 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+ConcreteProducer defines the type of function that creates a value of some
+concrete type to be stored in a field (or in an element of a slice or a map)
+of an interface type. The argument n is the sequence number of the produced
+value, different values of n must produce different values.
+
+See [StructVerifier.RegisterConcrete] for details.
+*/
+type ConcreteProducer func(n int) any
+
+/*
+RegisterConcrete registers producers of concrete values for the interface type
+iface. The registered producers are used to fill the fields with types:
+
+  * iface
+  * []iface
+  * map[K]iface, where K is a string kind type
+
+The producers are used one after another, so the slices and maps are filled by
+heterogeneous values. For example, to fill the fields of type []any and
+map[string]any with slices, maps and integers:
+
+  anyType := reflect.TypeOf((*any)(nil)).Elem()
+  sv.RegisterConcrete(anyType,
+      func(n int) any { return n },
+      func(n int) any { return []int{n, n + 1} },
+      func(n int) any { return map[string]any{"key": n} },
+  )
+
+To change such fields, the concrete values are changed by the Changer functions
+appropriate to their types, therefore, it is possible to reveal a clone that
+shares the slice or the map stored in the interface value with the original.
+
+The producers may return values of the verified structure type itself if it
+implements the interface, e.g. tree nodes that store their children as values
+of the interface type. The produced values may refer to each other or to
+themselves: each pointer is changed only once and the comparison of the clone
+with the original terminates on cycles, so the sharing of the produced values
+between the clone and the original is detected as for any other pointers.
+
+Registered producers take precedence over embedded Setter functions, so the
+embedded handlers for map[string]any are not used after registration of
+producers for the any type.
+*/
+func (sv *StructVerifier) RegisterConcrete(iface reflect.Type, producers ...ConcreteProducer) *StructVerifier {
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("RegisterConcrete: %q is not an interface type", iface))
+	}
+
+	if sv.concretes == nil {
+		sv.concretes = map[reflect.Type][]ConcreteProducer{}
+	}
+	sv.concretes[iface] = append(sv.concretes[iface], producers...)
+
+	return sv
+}
+
+// concreteIface returns the interface type which values are stored in the
+// value of type t (t itself, or element type of slice or map) if the producers
+// of concrete values have been registered for it
+func (sv *StructVerifier) concreteIface(t reflect.Type) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Interface:
+		// Use the type itself
+	case reflect.Slice:
+		t = t.Elem()
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, false
+		}
+		t = t.Elem()
+	default:
+		return nil, false
+	}
+
+	if t.Kind() != reflect.Interface || len(sv.concretes[t]) == 0 {
+		return nil, false
+	}
+
+	return t, true
+}
+
+// concreteValue creates a value of an interface type, slice or map of the
+// interface type using the registered producers. It returns false if the type
+// of v is not an appropriate type or no producers were registered
+func (fl *filler) concreteValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	iface, ok := fl.sv.concreteIface(v.Type())
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	t := v.Type()
+	// Number of elements - each producer is used at least twice
+	n := len(fl.sv.concretes[iface]) * initialSeed
+
+	switch t.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(t, 0, n)
+		for i := 0; i < n; i++ {
+			val, err := fl.produce(iface, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			s = reflect.Append(s, val)
+		}
+
+		return s, true, nil
+
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(t, n)
+		for i := 0; i < n; i++ {
+			key := reflect.ValueOf(fmt.Sprintf("key_%d", fl.state.seq)).Convert(t.Key())
+			val, err := fl.produce(iface, fmt.Sprintf("%s[%q]", path, key))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			m.SetMapIndex(key, val)
+		}
+
+		return m, true, nil
+
+	default:
+		val, err := fl.produce(iface, path)
+		return val, true, err
+	}
+}
+
+// produce creates the next concrete value for the interface type iface
+func (fl *filler) produce(iface reflect.Type, path string) (reflect.Value, error) {
+	producers := fl.sv.concretes[iface]
+	// Sequence numbers start from 1 to avoid zero values
+	fl.state.seq++
+	x := producers[(fl.state.seq - 1) % len(producers)](fl.state.seq)
+
+	if x == nil {
+		return reflect.Value{}, fmt.Errorf("concrete producer for %q returned nil value for %q", iface, path)
+	}
+
+	val := reflect.ValueOf(x)
+	if !val.Type().Implements(iface) {
+		return reflect.Value{}, fmt.Errorf("concrete producer for %q returned value of type %q" +
+			" that does not implement it for %q", iface, val.Type(), path)
+	}
+
+	// Convert the value to the interface type
+	rv := reflect.New(iface).Elem()
+	rv.Set(val)
+
+	return rv, nil
+}
+
+// changeConcrete changes the concrete values stored in v of interface type,
+// slice or map of interface type, if the concrete producers were registered
+// for the interface type. All stored values are changed
+func (ch *changer) changeConcrete(v reflect.Value) bool {
+	if _, ok := ch.sv.concreteIface(v.Type()); !ok {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			if ch.changeIface(v.Index(i)) {
+				changed = true
+			}
+		}
+		return changed
+
+	case reflect.Map:
+		changed := false
+		for _, key := range v.MapKeys() {
+			// Map values are not addressable, need to make a copy
+			val := reflect.New(v.Type().Elem()).Elem()
+			val.Set(v.MapIndex(key))
+			if ch.changeIface(val) {
+				v.SetMapIndex(key, val)
+				changed = true
+			}
+		}
+		return changed
+
+	default:
+		return ch.changeIface(v)
+	}
+}
+
+// changeIface changes the concrete value stored in the interface value v
+func (ch *changer) changeIface(v reflect.Value) bool {
+	if v.IsNil() {
+		return false
+	}
+
+	// The concrete value stored in the interface is not addressable,
+	// so change its copy and then store the copy back to the interface
+	val := reflect.New(v.Elem().Type()).Elem()
+	val.Set(v.Elem())
+	if !ch.change(val) {
+		return false
+	}
+	v.Set(val)
+
+	return true
+}
@@ -0,0 +1,307 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testIfaceStruct struct {
+	Any		any
+	Items	[]any
+	Props	map[string]any
+}
+
+// copyAny returns a deep copy of the concrete values used in the tests
+func copyAny(x any) any {
+	switch v := x.(type) {
+	case []int:
+		rv := make([]int, len(v))
+		copy(rv, v)
+		return rv
+	case map[string]any:
+		rv := make(map[string]any, len(v))
+		for k, val := range v {
+			rv[k] = copyAny(val)
+		}
+		return rv
+	default:
+		return x
+	}
+}
+
+// cloneIfaceStruct returns a clone of testIfaceStruct, the values stored
+// in the interfaces are copied by the copyVal function
+func cloneIfaceStruct(orig *testIfaceStruct, copyVal func(any) any) *testIfaceStruct {
+	rv := *orig
+
+	rv.Any = copyVal(orig.Any)
+	rv.Items = make([]any, 0, len(orig.Items))
+	for _, item := range orig.Items {
+		rv.Items = append(rv.Items, copyVal(item))
+	}
+	rv.Props = make(map[string]any, len(orig.Props))
+	for k, v := range orig.Props {
+		rv.Props[k] = copyVal(v)
+	}
+
+	return &rv
+}
+
+func newIfaceVerifier(copyVal func(any) any) *StructVerifier {
+	return NewStructVerifier(
+		func() any { return &testIfaceStruct{} },
+		func(x any) any {
+			orig, ok := x.(*testIfaceStruct)
+			if !ok {
+				panic(fmt.Sprintf("unsupported type to clone - %T, want - *testIfaceStruct", x))
+			}
+			return cloneIfaceStruct(orig, copyVal)
+		},
+	).RegisterConcrete(reflect.TypeOf((*any)(nil)).Elem(),
+		func(n int) any { return n },
+		func(n int) any { return []int{n, n + 1} },
+		func(n int) any { return map[string]any{"key": n} },
+	)
+}
+
+func TestRegisterConcrete(t *testing.T) {
+	if err := newIfaceVerifier(copyAny).Verify(); err != nil {
+		t.Errorf("verification of structure with interface fields failed: %v", err)
+	}
+}
+
+func TestRegisterConcreteShared(t *testing.T) {
+	// Copy containers but share the values stored in the interfaces
+	err := newIfaceVerifier(func(x any) any { return x }).Verify()
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares interface values with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestRegisterConcreteInvalid(t *testing.T) {
+	type shape interface { Area() int }
+
+	err := NewStructVerifier(
+		func() any { return &struct{S []shape}{} },
+		func(x any) any { return x },
+	).RegisterConcrete(reflect.TypeOf((*shape)(nil)).Elem(),
+		// int does not implement shape
+		func(n int) any { return n },
+	).Verify()
+
+	if !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
+type testArea interface {
+	Area() int
+}
+
+type testRect struct {
+	W, H	int
+}
+
+func (r *testRect) Area() int {
+	return r.W * r.H
+}
+
+type testShapes struct {
+	Shapes	[]testArea
+}
+
+// cloneShapes returns a clone of testShapes, if shareRects is true,
+// the new slice holds the same pointers as the original one
+func cloneShapes(x any, shareRects bool) any {
+	orig := x.(*testShapes)	//nolint:forcetypeassert
+
+	rv := &testShapes{Shapes: make([]testArea, 0, len(orig.Shapes))}
+	for _, s := range orig.Shapes {
+		if r, ok := s.(*testRect); ok && !shareRects {
+			s = &testRect{W: r.W, H: r.H}
+		}
+		rv.Shapes = append(rv.Shapes, s)
+	}
+
+	return rv
+}
+
+func TestRegisterConcreteElemPointers(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testShapes{} },
+		func(x any) any { return cloneShapes(x, false) },
+	).RegisterConcrete(reflect.TypeOf((*testArea)(nil)).Elem(),
+		func(n int) any { return &testRect{W: n, H: n + 1} },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of slice of interface values failed: %v", err)
+	}
+}
+
+func TestRegisterConcreteElemPointersShared(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testShapes{} },
+		func(x any) any { return cloneShapes(x, true) },
+	).RegisterConcrete(reflect.TypeOf((*testArea)(nil)).Elem(),
+		func(n int) any { return &testRect{W: n, H: n + 1} },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares element pointers with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestRegisterConcreteElemMissing(t *testing.T) {
+	// No producers are registered for the element type
+	sv := NewStructVerifier(
+		func() any { return &testShapes{} },
+		func(x any) any { return cloneShapes(x, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because no producers are registered for the element type")
+	case errors.As(err, new(*ErrSVOrigFill)):
+		if !strings.Contains(err.Error(), "clone.testArea") {
+			t.Errorf("error %q does not name the element type", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
+// testGrouper is implemented by testGroupNode that contains testGrouper values
+type testGrouper interface {
+	Size() int
+}
+
+type testGroupNode struct {
+	Name	string
+	Sizes	[]int
+	Parent	testGrouper
+	Members	[]testGrouper
+}
+
+func (g *testGroupNode) Size() int {
+	return len(g.Sizes)
+}
+
+// cloneGroupNode returns a deep copy of g, the nested groups are copied
+// unless shallow is set, cycles are preserved by the clones map
+func cloneGroupNode(g *testGroupNode, shallow bool, clones map[*testGroupNode]*testGroupNode) *testGroupNode {
+	if c, ok := clones[g]; ok {
+		return c
+	}
+
+	rv := &testGroupNode{Name: g.Name, Sizes: append([]int(nil), g.Sizes...)}
+	clones[g] = rv
+
+	copyGrouper := func(s testGrouper) testGrouper {
+		if nested, ok := s.(*testGroupNode); ok && nested != nil && !shallow {
+			return cloneGroupNode(nested, shallow, clones)
+		}
+		return s
+	}
+
+	rv.Parent = copyGrouper(g.Parent)
+	for _, m := range g.Members {
+		rv.Members = append(rv.Members, copyGrouper(m))
+	}
+
+	return rv
+}
+
+// nestedGroup produces groups of the same type as the verified structure
+func nestedGroup(n int) any {
+	return &testGroupNode{Name: fmt.Sprintf("nested_%d", n), Sizes: []int{n}}
+}
+
+// cyclicGroup produces groups that refer to themselves
+func cyclicGroup(n int) any {
+	g := &testGroupNode{Name: fmt.Sprintf("cyclic_%d", n), Sizes: []int{n}}
+	g.Parent = g
+	g.Members = []testGrouper{g}
+	return g
+}
+
+func TestRegisterConcreteSelfType(t *testing.T) {
+	grouper := reflect.TypeOf((*testGrouper)(nil)).Elem()
+
+	sv := NewStructVerifier(
+		func() any { return &testGroupNode{} },
+		func(x any) any {
+			return cloneGroupNode(x.(*testGroupNode), false, map[*testGroupNode]*testGroupNode{})	//nolint:forcetypeassert
+		},
+	).RegisterConcrete(grouper, nestedGroup)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of nested self-implementing interface fields failed: %v", err)
+	}
+
+	sv = NewStructVerifier(
+		func() any { return &testGroupNode{} },
+		func(x any) any {
+			return cloneGroupNode(x.(*testGroupNode), false, map[*testGroupNode]*testGroupNode{})	//nolint:forcetypeassert
+		},
+	).RegisterConcrete(grouper, cyclicGroup)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of cyclic self-implementing interface fields failed: %v", err)
+	}
+}
+
+func TestRegisterConcreteSelfTypeShallow(t *testing.T) {
+	grouper := reflect.TypeOf((*testGrouper)(nil)).Elem()
+
+	// Share the nested groups with the original
+	sv := NewStructVerifier(
+		func() any { return &testGroupNode{} },
+		func(x any) any {
+			return cloneGroupNode(x.(*testGroupNode), true, map[*testGroupNode]*testGroupNode{})	//nolint:forcetypeassert
+		},
+	).RegisterConcrete(grouper, nestedGroup)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares nested groups with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+
+	sv = NewStructVerifier(
+		func() any { return &testGroupNode{} },
+		func(x any) any {
+			return cloneGroupNode(x.(*testGroupNode), true, map[*testGroupNode]*testGroupNode{})	//nolint:forcetypeassert
+		},
+	).RegisterConcrete(grouper, cyclicGroup)
+
+	err = sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares cyclic groups with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
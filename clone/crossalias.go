@@ -0,0 +1,134 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrSVCrossFieldAlias represents an error that occurs when two different
+// slices of the clone share the backing array, while the slices of the
+// original at the same paths do not, see [StructVerifier.CheckCrossFieldAliasing].
+// First and Second contain the paths to the slices.
+type ErrSVCrossFieldAlias struct {
+	structVerifierError
+	First	string
+	Second	string
+}
+
+// sliceRef describes the backing array of the slice found by the path
+type sliceRef struct {
+	path		string
+	start, end	uintptr	// addresses of the backing array available to the slice
+}
+
+/*
+CheckCrossFieldAliasing enables an additional verification phase for cloners
+that allocate one array for several slices, e.g. to reduce allocations. The
+regular phases verify that each field of the clone is independent of the
+original, but two slices of the clone sharing the backing array, like a slice
+field and a slice stored in a map field, are not independent of each other:
+appending to one of them or changing its elements changes the other one.
+
+In this phase, all slices reachable from the exported fields of the clone
+(including the slices stored in nested structures, pointers, slices, arrays
+and map values) are collected with their backing arrays up to their
+capacities. If the arrays of two slices overlap, but the arrays of the slices
+of the original at the same paths do not, the *[ErrSVCrossFieldAlias] error
+with both paths is returned. Slices limited by the full slice expression, like
+buf[0:2:2], do not overlap with the rest of the array.
+*/
+func (sv *StructVerifier) CheckCrossFieldAliasing() *StructVerifier {
+	sv.crossAlias = true
+	return sv
+}
+
+// verifyCrossAliasing checks that slices of the clone of orig do not share
+// backing arrays unless the slices of orig at the same paths do
+func (sv *StructVerifier) verifyCrossAliasing(orig any) error {
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	origSlices := map[string]sliceRef{}
+	for _, ref := range sv.collectSlices(reflect.ValueOf(orig).Elem(), "", 0, nil) {
+		origSlices[ref.path] = ref
+	}
+
+	slices := sv.collectSlices(reflect.ValueOf(clone).Elem(), "", 0, nil)
+	for i, a := range slices {
+		for _, b := range slices[i+1:] {
+			if !a.overlaps(b) {
+				continue
+			}
+
+			oa, okA := origSlices[a.path]
+			ob, okB := origSlices[b.path]
+			if okA && okB && oa.overlaps(ob) {
+				// The original shares the array the same way
+				continue
+			}
+
+			return &ErrSVCrossFieldAlias{
+				structVerifierError:	newErrSV("slices %q and %q of the CLONE share the backing array," +
+											" but the slices of the ORIGINAL do not: %s", a.path, b.path, sv.dump(clone)),
+				First:					a.path,
+				Second:					b.path,
+			}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// overlaps reports whether the backing arrays of the slices overlap
+func (ref sliceRef) overlaps(other sliceRef) bool {
+	return ref.start < other.end && other.start < ref.end
+}
+
+// collectSlices appends the slices reachable from v to refs. The paths are
+// built the same way as by findShared
+func (sv *StructVerifier) collectSlices(v reflect.Value, path string, depth int, refs []sliceRef) []sliceRef {
+	if depth > sv.maxDepth {
+		// Too deep, stop here
+		return refs
+	}
+
+	switch v.Kind() { //nolint:exhaustive	// other kinds do not contain slices
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return refs
+		}
+		if v.Kind() == reflect.Pointer {
+			path = "*" + path
+		}
+		return sv.collectSlices(v.Elem(), path, depth + 1, refs)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if verifiable(f) {
+				refs = sv.collectSlices(v.Field(i), fieldPath(path, f.Name), depth + 1, refs)
+			}
+		}
+
+	case reflect.Slice:
+		if size := v.Type().Elem().Size(); !v.IsNil() && v.Cap() != 0 && size != 0 {
+			refs = append(refs, sliceRef{path: path, start: v.Pointer(), end: v.Pointer() + uintptr(v.Cap()) * size})
+		}
+		fallthrough
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			refs = sv.collectSlices(v.Index(i), fmt.Sprintf("%s[%d]", path, i), depth + 1, refs)
+		}
+
+	case reflect.Map:
+		for _, key := range sortedKeys(v) {
+			refs = sv.collectSlices(v.MapIndex(key), fmt.Sprintf("%s[%v]", path, key), depth + 1, refs)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,103 @@
+package clone
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+type testIndex struct {
+	Keys	[]int
+	ByName	map[string][]int
+}
+
+// cloneIndex returns a clone of testIndex, all slices of the clone are
+// allocated in one array, limit sets the capacities of the slices
+func cloneIndex(x any, limit bool) any {
+	orig := x.(*testIndex)	//nolint:forcetypeassert
+
+	n := len(orig.Keys)
+	for _, v := range orig.ByName {
+		n += len(v)
+	}
+	buf := make([]int, 0, n)
+
+	// sub appends s to buf and returns the part of buf with s
+	sub := func(s []int) []int {
+		lo := len(buf)
+		buf = append(buf, s...)
+		if limit {
+			return buf[lo:len(buf):len(buf)]
+		}
+		return buf[lo:len(buf)]
+	}
+
+	rv := &testIndex{Keys: sub(orig.Keys), ByName: make(map[string][]int, len(orig.ByName))}
+	keys := make([]string, 0, len(orig.ByName))
+	for k := range orig.ByName {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rv.ByName[k] = sub(orig.ByName[k])
+	}
+
+	return rv
+}
+
+func TestCheckCrossFieldAliasing(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testIndex{} },
+		func(x any) any { return cloneIndex(x, true) },
+		WithCheckCrossFieldAliasing(),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of slices with limited capacities failed: %v", err)
+	}
+}
+
+func TestCheckCrossFieldAliasingShared(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testIndex{} },
+		func(x any) any { return cloneIndex(x, false) },
+		WithCheckCrossFieldAliasing(),
+	)
+
+	err := sv.Verify()
+	var errAlias *ErrSVCrossFieldAlias
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because slices of the clone share one array")
+	case errors.As(err, &errAlias):
+		if errAlias.First != "Keys" || errAlias.Second == "" {
+			t.Errorf("unexpected paths of aliased slices: %q, %q", errAlias.First, errAlias.Second)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCrossFieldAlias", err, err)
+	}
+}
+
+func TestCheckCrossFieldAliasingOrig(t *testing.T) {
+	// Slices shared by the original the same way are allowed
+	sv := NewStructVerifierWith(
+		func() any { return &testIndex{} },
+		func(x any) any { return cloneIndex(x, false) },
+	)
+	orig := &testIndex{Keys: []int{1, 2}}
+	orig.ByName = map[string][]int{"a": {3}}
+	err := sv.verifyCrossAliasing(orig)
+	switch {
+	case err == nil:
+		t.Errorf("slices are shared by the clone only, but no error returned")
+	case errors.As(err, new(*ErrSVCrossFieldAlias)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCrossFieldAlias", err, err)
+	}
+
+	orig.ByName["a"] = orig.Keys[1:]
+	if err := sv.verifyCrossAliasing(orig); err != nil {
+		t.Errorf("slices shared by the original the same way: %v", err)
+	}
+}
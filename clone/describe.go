@@ -0,0 +1,160 @@
+package clone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+Describe returns a human-readable summary of the verifier configuration: the
+verified type, the numbers of user-defined Setter and Changer functions, the
+registered concrete producers and comparators, the initial seed state (see
+[SeedState]), the limits and the active modes. For example:
+
+  StructVerifier of *pkg.Config:
+    user setters: 1, with field names: 0
+    user changers: 1
+    concrete producers: none
+    comparators: none
+    seed state: int=0 int64=0 float=0 rune=0 strings=2 big=0 string=0 duration=0 ip=0 set=0 byte=0 seq=0
+    max depth: 8
+    parallelism: 1
+    max error length: 4096
+    modes: strict setters, change all fields
+
+It is useful to debug the verifier set up by shared helpers and to make the
+test output self-documenting, e.g. t.Log(sv.Describe()).
+*/
+func (sv *StructVerifier) Describe() string {
+	buf := &strings.Builder{}
+
+	fmt.Fprintf(buf, "StructVerifier of %T:\n", sv.creator())
+	fmt.Fprintf(buf, "  user setters: %d, with field names: %d\n", len(sv.setters), len(sv.fieldSetters))
+	fmt.Fprintf(buf, "  user changers: %d\n", len(sv.changers))
+
+	producers := make([]string, 0, len(sv.concretes))
+	for iface, prods := range sv.concretes {
+		producers = append(producers, fmt.Sprintf("%v (%d)", iface, len(prods)))
+	}
+	fmt.Fprintf(buf, "  concrete producers: %s\n", describeList(sortedStrings(producers)))
+
+	comparators := make([]string, 0, len(sv.cmp.typeEqual))
+	for t := range sv.cmp.typeEqual {
+		comparators = append(comparators, t.String())
+	}
+	fmt.Fprintf(buf, "  comparators: %s\n", describeList(sortedStrings(comparators)))
+	if len(sv.cmp.fieldEqual) != 0 {
+		fields := make([]string, 0, len(sv.cmp.fieldEqual))
+		for field := range sv.cmp.fieldEqual {
+			fields = append(fields, field)
+		}
+		fmt.Fprintf(buf, "  field comparators: %s\n", describeList(sortedStrings(fields)))
+	}
+
+	if len(sv.optionals) != 0 {
+		optionals := make([]string, 0, len(sv.optionals))
+		for t := range sv.optionals {
+			optionals = append(optionals, t.String())
+		}
+		fmt.Fprintf(buf, "  optional types: %s\n", describeList(sortedStrings(optionals)))
+	}
+	if len(sv.externals) != 0 {
+		externals := make([]string, 0, len(sv.externals))
+		for t := range sv.externals {
+			externals = append(externals, t.String())
+		}
+		fmt.Fprintf(buf, "  external types: %s\n", describeList(sortedStrings(externals)))
+	}
+	if len(sv.aliasChecks) != 0 {
+		checks := make([]string, 0, len(sv.aliasChecks))
+		for t := range sv.aliasChecks {
+			checks = append(checks, t.String())
+		}
+		fmt.Fprintf(buf, "  alias checks: %s\n", describeList(sortedStrings(checks)))
+	}
+	if len(sv.methodChecks) != 0 {
+		methods := make([]string, 0, len(sv.methodChecks))
+		for _, mc := range sv.methodChecks {
+			methods = append(methods, mc.describe())
+		}
+		fmt.Fprintf(buf, "  method checks: %s\n", describeList(methods))
+	}
+
+	fmt.Fprintf(buf, "  seed state: %v\n", sv.state)
+	fmt.Fprintf(buf, "  max depth: %d\n", sv.maxDepth)
+	parallelism := sv.parallelism
+	if parallelism < 1 {
+		// Fields are verified one by one
+		parallelism = 1
+	}
+	fmt.Fprintf(buf, "  parallelism: %d\n", parallelism)
+	fmt.Fprintf(buf, "  max error length: %d\n", sv.maxErrLen)
+
+	if len(sv.accessors) != 0 {
+		fmt.Fprintf(buf, "  accessors: %d\n", len(sv.accessors))
+	}
+	if len(sv.sharedFields) != 0 {
+		shared := make([]string, 0, len(sv.sharedFields))
+		for field := range sv.sharedFields {
+			shared = append(shared, field)
+		}
+		fmt.Fprintf(buf, "  shared fields: %s\n", describeList(sortedStrings(shared)))
+	}
+
+	fmt.Fprintf(buf, "  modes: %s\n", describeList(sv.modes()))
+
+	return buf.String()
+}
+
+// modes returns the names of the enabled verification modes
+func (sv *StructVerifier) modes() []string {
+	var modes []string
+	for _, mode := range []struct {
+		on		bool
+		name	string
+	}{
+		{sv.strictSetters,			"strict setters"},
+		{sv.allFields,				"change all fields"},
+		{sv.nilIfaces,				"check nil interfaces"},
+		{sv.nilReceiver,			"check nil receiver"},
+		{sv.insertKeys,				"insert map keys"},
+		{sv.nilPointers,			"check nil pointers"},
+		{sv.crossAlias,				"check cross-field aliasing"},
+		{sv.sparse,					"check sparse fields"},
+		{sv.emptyCap,				"check empty capacity"},
+		{sv.deterministic,			"check deterministic"},
+		{sv.nestedCloners,			"nested cloners"},
+		{sv.partial,				"allow partial"},
+		{sv.cmp.nilEmptyEqual,		"treat nil and empty equal"},
+		{sv.propagatePanics,		"propagate panics"},
+		{sv.mapOneEntry,			"change one map entry"},
+		{sv.mapSlices,				"map slice values"},
+		{sv.mapNestDepth != 0,		fmt.Sprintf("map nested values (depth %d)", sv.mapNestDepth)},
+		{sv.nonFinite,				"non-finite floats"},
+		{sv.differ != nil,			"external differ"},
+		{sv.repro,					"capture repro"},
+		{sv.shuffle,				fmt.Sprintf("shuffle fields (seed %d)", sv.shuffleSeed)},
+	} {
+		if mode.on {
+			modes = append(modes, mode.name)
+		}
+	}
+
+	return modes
+}
+
+// describeList returns the comma-separated list of items or "none" if it is empty
+func describeList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+
+	return strings.Join(items, ", ")
+}
+
+// sortedStrings sorts items and returns them, it is used for items collected from maps
+func sortedStrings(items []string) []string {
+	sort.Strings(items)
+	return items
+}
@@ -0,0 +1,32 @@
+package clone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testRepro{} },
+		func(x any) any { return x },
+		WithSetters(intSliceSetter),
+		WithSeed(3),
+		WithComparator(func(a, b float64) bool { return a == b }),
+	).RegisterConcrete(reflect.TypeOf((*any)(nil)).Elem(), func(n int) any { return n }).
+		StrictSetters().ChangeAllFields()
+
+	want := `StructVerifier of *clone.testRepro:
+  user setters: 1, with field names: 0
+  user changers: 0
+  concrete producers: interface {} (1)
+  comparators: float64
+  seed state: int=3 int64=3 float=3 rune=3 strings=5 big=3 string=3 duration=3 ip=3 set=3 byte=3 seq=3
+  max depth: 8
+  parallelism: 1
+  max error length: 4096
+  modes: strict setters, change all fields
+`
+	if got := sv.Describe(); got != want {
+		t.Errorf("got description:\n%s\nwant:\n%s", got, want)
+	}
+}
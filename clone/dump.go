@@ -0,0 +1,44 @@
+package clone
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxErrorLen	=	4096	// default maximum length of values rendered in errors
+	truncatedMark		=	"...(truncated)"
+)
+
+/*
+SetMaxErrorLen sets the maximum length of each value rendering (%#v) included
+into error messages. Longer renderings are truncated and marked by the
+"...(truncated)" suffix, field names and error types are not affected. It
+prevents flooding logs for structures with large slices or maps. The value 0
+disables truncation. Default length is 4096 bytes.
+*/
+func (sv *StructVerifier) SetMaxErrorLen(n int) *StructVerifier {
+	if n < 0 {
+		panic(fmt.Sprintf("SetMaxErrorLen: negative length %d", n))
+	}
+
+	sv.maxErrLen = n
+	return sv
+}
+
+// dump returns the Go-syntax representation of v truncated to the maximum
+// length of values in error messages
+func (sv *StructVerifier) dump(v any) string {
+	s := fmt.Sprintf("%#v", v)
+	if sv.maxErrLen == 0 || len(s) <= sv.maxErrLen {
+		return s
+	}
+
+	// Do not split a multi-byte character
+	n := sv.maxErrLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + truncatedMark
+}
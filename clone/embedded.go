@@ -2,39 +2,292 @@ package clone
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
 	"strings"
 	"reflect"
+	"sort"
+	"time"
 )
 
-const initialSeed = 2
+const (
+	initialSeed	=	2
+	firstRune	=	'α'	// first code point used to generate runes
+	bigShift	=	100	// shift of the big.Int values to exceed the machine word
+	floatFraction	=	0.5	// fractional part of the float values
+)
+
+// embTypes is the list of types supported by embedded setters and changers
+var embTypes = []reflect.Type{
+	reflect.TypeOf(rune(0)),
+	reflect.TypeOf([]rune(nil)),
+	reflect.TypeOf(int(0)),
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(float64(0)),
+	reflect.TypeOf(float32(0)),
+	reflect.TypeOf(""),
+	reflect.TypeOf([]int(nil)),
+	reflect.TypeOf([]int64(nil)),
+	reflect.TypeOf([]string(nil)),
+	reflect.TypeOf(map[string]any(nil)),
+	reflect.TypeOf((*big.Int)(nil)),
+	reflect.TypeOf((*big.Rat)(nil)),
+	reflect.TypeOf(time.Duration(0)),
+	reflect.TypeOf([]time.Duration(nil)),
+	reflect.TypeOf(net.IP(nil)),
+	reflect.TypeOf(netip.Addr{}),
+	reflect.TypeOf(map[string]struct{}(nil)),
+	reflect.TypeOf([]byte(nil)),
+	reflect.TypeOf([][]byte(nil)),
+}
+
+/*
+SupportedTypes returns the list of types supported by the embedded Setter and
+Changer functions provided by [EmbSetters] and [EmbChangers]. It can be used to
+decide which user-defined Setter and Changer functions are required for a
+structure, by comparing the types of its fields with the returned list.
+
+Note that the named types with the same underlying type as one of the returned
+types, pointers to the returned types, as well as structures, slices and maps
+containing them, are also handled automatically by [StructVerifier.Verify].
+*/
+func SupportedTypes() []reflect.Type {
+	// Return a copy to prevent modification of the list
+	return append([]reflect.Type(nil), embTypes...)
+}
 
 //nolint:cyclop	// In fact, there are no cyclops there
 /*
 EmbSetters returns a set of embedded [Setter] functions for the following field types:
 
+  * rune (int32)
+  * []rune
   * int
   * int64
+  * float64
+  * float32
+  * string
   * []int
   * []int64
   * []string
   * map[string]any
+  * *big.Int
+  * *big.Rat
+  * time.Duration
+  * []time.Duration
+  * net.IP
+  * netip.Addr
+  * map[string]struct{}
+  * []byte
+  * [][]byte
+
+Since rune is an alias for int32, the rune handlers are applied to the int32
+fields too.
 
 */
 func EmbSetters() []Setter {
-	var i64v int64
-	var intVal int
-	nStrs := int(initialSeed)
+	return embSetters(0)
+}
+
+// embSetters returns a set of embedded setters, initial values of which
+// are shifted by the seed value
+func embSetters(seed int) []Setter {
+	st := newSeedState(seed)
+	return st.setters()
+}
+
+// changeFloat returns the changed value of f, the product of zero and non-finite
+// values is the same value, so they are replaced by the finite value
+func changeFloat(f float64) float64 {
+	if f == 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return floatFraction
+	}
+
+	return f * initialSeed
+}
+
+// nonFiniteSetter returns the setter of float64 and float32 values that produces
+// NaN, +Inf and -Inf in turn, see [StructVerifier.FillNonFiniteFloats]
+func (st *SeedState) nonFiniteSetter() Setter {
+	return func(v reflect.Value) any {
+		k := v.Kind()
+		if k != reflect.Float64 && k != reflect.Float32 || v.Type().PkgPath() != "" {
+			return nil
+		}
+
+		st.floatVal++
+
+		var f float64
+		//nolint:gomnd	// NaN, +Inf and -Inf in turn
+		switch st.floatVal % 3 {
+		case 0:
+			f = math.NaN()
+		case 1:
+			f = math.Inf(1)
+		default:
+			f = math.Inf(-1)
+		}
+
+		if k == reflect.Float32 {
+			return float32(f)
+		}
+		return f
+	}
+}
+
+// mapSliceSetter returns the setter of map[string]any values that holds slices
+// along with int values, see [StructVerifier.SetMapSliceValues]. The setter
+// advances the state st the same way as the embedded map[string]any setter
+func (st *SeedState) mapSliceSetter() Setter {
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().(map[string]any); !ok {
+			return nil
+		}
+
+		m := make(map[string]any, st.nStrs)
+		baseChar := fmt.Sprintf("%c", ('a' - initialSeed) + st.nStrs % ('z' - 'a'))
+		for i := 0; i < st.nStrs; i++ {
+			key := strings.Repeat(baseChar+"_", st.nStrs+i)
+			//nolint:gomnd	// int, []string and []int values in turn
+			switch i % 3 {
+			case 0:
+				m[key] = (i+1) * 3 / 2
+			case 1:
+				m[key] = []string{key, strings.ToUpper(key)}
+			default:
+				m[key] = []int{i, (i+1) * 3 / 2}
+			}
+		}
+		st.nStrs++
+
+		return m
+	}
+}
+
+// changeAnyMap changes values of the map[string]any m in place, see the
+// embedded map[string]any changer. Nested maps are changed recursively, if
+// allEntries is false, only the entry with the first key in sorted order is changed
+func changeAnyMap(m map[string]any, allEntries bool) bool {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if !allEntries && len(keys) > 1 {
+		keys = keys[:1]
+	}
+
+	changed := false
+	for _, k := range keys {
+		switch x := m[k].(type) {
+		case int:
+			m[k] = x * initialSeed
+		case string:
+			m[k] = x + "_"
+		case []string:
+			// Change the slice in place to detect slices shared with the original
+			if len(x) == 0 {
+				continue
+			}
+			x[len(x)-1] += "_"
+		case []int:
+			if len(x) == 0 {
+				continue
+			}
+			x[len(x)-1] *= initialSeed
+		case map[string]any:
+			// Change the nested map in place to detect maps shared with the original
+			if !changeAnyMap(x, allEntries) {
+				continue
+			}
+		default:
+			// Unsupported value type, leave it as is
+			continue
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// nestedMapKey is the key of nested maps in map[string]any values,
+// see [StructVerifier.SetMapNestedValues]
+const nestedMapKey = "nested"
+
+// addedMapKey is the key of the entry added to empty map[string]any values by the changer
+const addedMapKey = "added"
+
+// nestedMapSetter returns the setter of map[string]any values that holds depth
+// levels of nested maps. Each map is produced by the map[string]any setter from
+// base, so nested maps hold the same kinds of values as the top-level map
+func (st *SeedState) nestedMapSetter(depth int, base []Setter) Setter {
+	var nested func(v reflect.Value, depth int) any
+	nested = func(v reflect.Value, depth int) any {
+		x, ok := trySetters(base, v)
+		if !ok {
+			return nil
+		}
+
+		m := x.Interface().(map[string]any)	//nolint:forcetypeassert	// produced for map[string]any
+		if depth > 0 {
+			m[nestedMapKey] = nested(v, depth - 1)
+		}
+
+		return m
+	}
+
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().(map[string]any); !ok {
+			return nil
+		}
 
+		return nested(v, depth)
+	}
+}
+
+// setters returns a set of embedded setters that generate values starting
+// from the state st, the state is advanced by the returned setters
+func (st *SeedState) setters() []Setter {
 	return []Setter {
+		// rune - should be placed before any int32 handler
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(rune); !ok {
+				return nil
+			}
+
+			st.runeVal++
+
+			return firstRune + st.runeVal
+		},
+
+		// []rune
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().([]rune); !ok {
+				return nil
+			}
+
+			st.runeVal++
+
+			l := int(st.runeVal) * initialSeed	// slice length
+			s := make([]rune, 0, l)
+			for i := 0; i < l; i++ {
+				s = append(s, firstRune + st.runeVal + rune(i))
+			}
+
+			return s
+		},
+
 		// int
 		func(v reflect.Value) any {
 			if _, ok := v.Interface().(int); !ok {
 				return nil
 			}
 
-			intVal++
+			st.intVal++
 
-			return intVal
+			return st.intVal
 		},
 
 		// int64
@@ -43,9 +296,42 @@ func EmbSetters() []Setter {
 				return nil
 			}
 
-			i64v++
+			st.i64v++
+
+			return st.i64v
+		},
+
+		// float64 - finite values only, see StructVerifier.FillNonFiniteFloats
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(float64); !ok {
+				return nil
+			}
+
+			st.floatVal++
+
+			return float64(st.floatVal) + floatFraction
+		},
+
+		// float32 - finite values only, see StructVerifier.FillNonFiniteFloats
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(float32); !ok {
+				return nil
+			}
+
+			st.floatVal++
 
-			return i64v
+			return float32(st.floatVal) + floatFraction
+		},
+
+		// string
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(string); !ok {
+				return nil
+			}
+
+			st.strVal++
+
+			return fmt.Sprintf("%c_%d", 'a' + st.strVal % ('z' - 'a'), st.strVal)
 		},
 
 		// []int
@@ -54,12 +340,12 @@ func EmbSetters() []Setter {
 				return nil
 			}
 
-			intVal++
+			st.intVal++
 
-			l := intVal * initialSeed	// slice length
+			l := st.intVal * initialSeed	// slice length
 			s := make([]int, 0, l)
 			for i := 0; i < l; i++ {
-				s = append(s, intVal + i)
+				s = append(s, st.intVal + i)
 			}
 
 			return s
@@ -71,12 +357,12 @@ func EmbSetters() []Setter {
 				return nil
 			}
 
-			i64v++
+			st.i64v++
 
-			l := i64v * initialSeed	// slice length
+			l := st.i64v * initialSeed	// slice length
 			s := make([]int64, 0, l)
 			for i := int64(0); i < l; i++ {
-				s = append(s, i64v + i)
+				s = append(s, st.i64v + i)
 			}
 
 			return s
@@ -88,12 +374,12 @@ func EmbSetters() []Setter {
 				return nil
 			}
 
-			s := make([]string, 0, nStrs + 1)
-			baseChar := fmt.Sprintf("%c", ('a' - initialSeed) + nStrs % ('z' - 'a'))
-			for i := 0; i < nStrs; i++ {
-				s = append(s, strings.Repeat(baseChar+"_", nStrs))
+			s := make([]string, 0, st.nStrs + 1)
+			baseChar := fmt.Sprintf("%c", ('a' - initialSeed) + st.nStrs % ('z' - 'a'))
+			for i := 0; i < st.nStrs; i++ {
+				s = append(s, strings.Repeat(baseChar+"_", st.nStrs))
 			}
-			nStrs++
+			st.nStrs++
 
 			return s
 		},
@@ -104,32 +390,202 @@ func EmbSetters() []Setter {
 				return nil
 			}
 
-			m := make(map[string]any, nStrs)
-			baseChar := fmt.Sprintf("%c", ('a' - initialSeed) + nStrs % ('z' - 'a'))
-			for i := 0; i < nStrs; i++ {
+			m := make(map[string]any, st.nStrs)
+			baseChar := fmt.Sprintf("%c", ('a' - initialSeed) + st.nStrs % ('z' - 'a'))
+			for i := 0; i < st.nStrs; i++ {
 				//nolint:gomnd	// Yes, some kind of pseudo-random generation magic here
-				m[strings.Repeat(baseChar+"_", nStrs+i)] = (i+1) * 3 / 2
+				m[strings.Repeat(baseChar+"_", st.nStrs+i)] = (i+1) * 3 / 2
+			}
+			st.nStrs++
+
+			return m
+		},
+
+		// *big.Int - should be placed before the generic pointer handler
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(*big.Int); !ok {
+				return nil
+			}
+
+			st.bigVal++
+
+			// Use a value that does not fit into a single machine word
+			x := new(big.Int).Lsh(big.NewInt(st.bigVal), bigShift)
+
+			return x.Add(x, big.NewInt(st.bigVal))
+		},
+
+		// *big.Rat - should be placed before the generic pointer handler
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(*big.Rat); !ok {
+				return nil
+			}
+
+			st.bigVal++
+
+			return new(big.Rat).SetFrac(big.NewInt(st.bigVal), big.NewInt(st.bigVal + 1))
+		},
+
+		// time.Duration - should be placed before any int64 handler of named types
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(time.Duration); !ok {
+				return nil
+			}
+
+			st.durVal++
+
+			return st.durVal * time.Minute + st.durVal * time.Second
+		},
+
+		// []time.Duration
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().([]time.Duration); !ok {
+				return nil
+			}
+
+			st.durVal++
+
+			l := int(st.durVal) * initialSeed	// slice length
+			s := make([]time.Duration, 0, l)
+			for i := 0; i < l; i++ {
+				s = append(s, st.durVal * time.Minute + time.Duration(i) * time.Second)
+			}
+
+			return s
+		},
+
+		// net.IP - should be placed before the generic []byte handler of named types
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(net.IP); !ok {
+				return nil
+			}
+
+			st.ipVal++
+
+			// Use the private network 10.0.0.0/8, the last octet is never zero
+			return net.ParseIP(fmt.Sprintf("10.%d.%d.%d", st.ipVal / 254 / 256 % 256, st.ipVal / 254 % 256, st.ipVal % 254 + 1))
+		},
+
+		// netip.Addr - cannot be filled generically due to unexported fields
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(netip.Addr); !ok {
+				return nil
+			}
+
+			st.ipVal++
+
+			// Use the documentation prefix 2001:db8::/32
+			return netip.MustParseAddr(fmt.Sprintf("2001:db8::%x", st.ipVal))
+		},
+
+		// map[string]struct{} - set of strings, each field gets its own set of keys
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().(map[string]struct{}); !ok {
+				return nil
+			}
+
+			st.setVal++
+
+			m := make(map[string]struct{}, initialSeed + 1)
+			for i := 0; i <= initialSeed; i++ {
+				m[fmt.Sprintf("set%d_key%d", st.setVal, i)] = struct{}{}
 			}
-			nStrs++
 
 			return m
 		},
+
+		// []byte - should be placed before the generic slice handler
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().([]byte); !ok {
+				return nil
+			}
+
+			st.byteVal++
+
+			return []byte(fmt.Sprintf("bytes_%d", st.byteVal))
+		},
+
+		// [][]byte - each inner slice has its own buffer
+		func(v reflect.Value) any {
+			if _, ok := v.Interface().([][]byte); !ok {
+				return nil
+			}
+
+			st.byteVal++
+
+			s := make([][]byte, 0, initialSeed + 1)
+			for i := 0; i <= initialSeed; i++ {
+				s = append(s, []byte(fmt.Sprintf("row%d_%d", st.byteVal, i)))
+			}
+
+			return s
+		},
 	}
 }
 
 /*
 EmbChangers returns a set of embedded [Changer] functions for the following field types:
 
+  * rune (int32)
+  * []rune
   * int
   * int64
+  * float64
+  * float32
+  * string
   * []int
   * []int64
   * []string
   * map[string]any
-
+  * *big.Int
+  * *big.Rat
+  * time.Duration
+  * []time.Duration
+  * net.IP
+  * netip.Addr
+  * map[string]struct{}
+  * []byte
+  * [][]byte
+
+The map[string]any changer changes all values of the map, including values of
+nested map[string]any maps. The changers of slices and of map[string]any add an
+element to nil and empty values, so values not produced by the embedded
+setters, like the ones passed to [StructVerifier.VerifyInstance], are changed too.
 */
 func EmbChangers() []Changer {
+	return embChangers(true)
+}
+
+// embChangers returns the set of embedded changers, if allEntries is false,
+// the map changers change only the entry with the first key in sorted order
+func embChangers(allEntries bool) []Changer {
 	return []Changer{
+		// rune - replace the value by the next code point
+		func(v reflect.Value) bool {
+			r, ok := v.Interface().(rune)
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(r + 1))
+			return true
+		},
+
+		// []rune - replace the last rune by the next code point or append one if empty
+		func(v reflect.Value) bool {
+			rs, ok := v.Interface().([]rune)
+			if !ok {
+				return false
+			}
+
+			if len(rs) == 0 {
+				v.Set(reflect.ValueOf(append(rs, firstRune)))
+			} else {
+				rs[len(rs)-1]++
+			}
+
+			return true
+		},
+
 		// int - mult the value to initialSeed (2)
 		func(v reflect.Value) bool {
 			iv, ok := v.Interface().(int)
@@ -150,57 +606,254 @@ func EmbChangers() []Changer {
 			return true
 		},
 
-		// []int - mult the last value in the slice to initialSeed (2)
+		// float64 - mult the value to initialSeed (2), NaN and Inf are replaced by a finite value
+		func(v reflect.Value) bool {
+			f, ok := v.Interface().(float64)
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(changeFloat(f)))
+			return true
+		},
+
+		// float32 - mult the value to initialSeed (2), NaN and Inf are replaced by a finite value
+		func(v reflect.Value) bool {
+			f, ok := v.Interface().(float32)
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(float32(changeFloat(float64(f)))))
+			return true
+		},
+
+		// string - append the underscore to the value
+		func(v reflect.Value) bool {
+			s, ok := v.Interface().(string)
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(s + "_"))
+			return true
+		},
+
+		// []int - mult the last value in the slice to initialSeed (2) or append one if empty
 		func(v reflect.Value) bool {
 			is, ok := v.Interface().([]int)
 			if !ok {
 				return false
 			}
 
-			is[len(is)-1] *= initialSeed
+			if len(is) == 0 {
+				v.Set(reflect.ValueOf(append(is, initialSeed)))
+			} else {
+				is[len(is)-1] *= initialSeed
+			}
 
 			return true
 		},
 
-		// []int64 - mult the last value in the slice to initialSeed (2)
+		// []int64 - mult the last value in the slice to initialSeed (2) or append one if empty
 		func(v reflect.Value) bool {
 			is, ok := v.Interface().([]int64)
 			if !ok {
 				return false
 			}
 
-			is[len(is)-1] *= initialSeed
+			if len(is) == 0 {
+				v.Set(reflect.ValueOf(append(is, initialSeed)))
+			} else {
+				is[len(is)-1] *= initialSeed
+			}
 
 			return true
 		},
 
-		// []string - concatenate the last value in the slice with itself
+		// []string - concatenate the last value in the slice with itself or append
+		// the underscore if empty
 		func(v reflect.Value) bool {
 			ss, ok := v.Interface().([]string)
 			if !ok {
 				return false
 			}
 
-			ss[len(ss)-1] += ss[len(ss)-1]
+			if len(ss) == 0 {
+				v.Set(reflect.ValueOf(append(ss, "_")))
+			} else {
+				ss[len(ss)-1] += ss[len(ss)-1]
+			}
 
 			return true
 		},
 
-		// map[string]any - mult each value to initialSeed (2)
+		// map[string]any - mult int values to initialSeed (2), change the last
+		// element of slice values and nested maps in place, other values are not
+		// changed. The map is reported as changed even if it has no values to
+		// change, the type itself is supported. An int entry is added to the
+		// empty map, the nil map is replaced by the map with such entry
 		func(v reflect.Value) bool {
 			m, ok := v.Interface().(map[string]any)
 			if !ok {
 				return false
 			}
 
-			// Update only one random value if exists
-			for k, v := range m {
-				//nolint:forcetypeassert // Mult the value to initialSeed (2)
-				m[k] = v.(int) * initialSeed
-				break
+			switch {
+			case m == nil:
+				v.Set(reflect.ValueOf(map[string]any{addedMapKey: initialSeed}))
+			case len(m) == 0:
+				m[addedMapKey] = initialSeed
+			default:
+				changeAnyMap(m, allEntries)
+			}
+
+			return true
+		},
+
+		// *big.Int - add initialSeed (2) to the value
+		func(v reflect.Value) bool {
+			x, ok := v.Interface().(*big.Int)
+			if !ok || x == nil {
+				return false
+			}
+
+			x.Add(x, big.NewInt(initialSeed))
+
+			return true
+		},
+
+		// *big.Rat - add initialSeed (2) to the value
+		func(v reflect.Value) bool {
+			x, ok := v.Interface().(*big.Rat)
+			if !ok || x == nil {
+				return false
+			}
+
+			x.Add(x, big.NewRat(initialSeed, 1))
+
+			return true
+		},
+
+		// time.Duration - add initialSeed (2) seconds to the value
+		func(v reflect.Value) bool {
+			d, ok := v.Interface().(time.Duration)
+			if !ok {
+				return false
+			}
+			v.Set(reflect.ValueOf(d + initialSeed * time.Second))
+			return true
+		},
+
+		// []time.Duration - add initialSeed (2) seconds to the last value in the slice
+		// or append one if empty
+		func(v reflect.Value) bool {
+			ds, ok := v.Interface().([]time.Duration)
+			if !ok {
+				return false
+			}
+
+			if len(ds) == 0 {
+				v.Set(reflect.ValueOf(append(ds, initialSeed * time.Second)))
+			} else {
+				ds[len(ds)-1] += initialSeed * time.Second
+			}
+
+			return true
+		},
+
+		// net.IP - increment the last byte of the address or set 10.0.0.1 if empty
+		func(v reflect.Value) bool {
+			ip, ok := v.Interface().(net.IP)
+			if !ok {
+				return false
+			}
+
+			if len(ip) == 0 {
+				v.Set(reflect.ValueOf(net.ParseIP("10.0.0.1")))
+			} else {
+				ip[len(ip)-1]++
+			}
+
+			return true
+		},
+
+		// netip.Addr - replace the address by the next one, or by the previous one
+		// if it is the last address, or set 2001:db8::1 if invalid (zero)
+		func(v reflect.Value) bool {
+			addr, ok := v.Interface().(netip.Addr)
+			if !ok {
+				return false
+			}
+
+			switch {
+			case !addr.IsValid():
+				addr = netip.MustParseAddr("2001:db8::1")
+			case addr.Next().IsValid():
+				addr = addr.Next()
+			default:
+				addr = addr.Prev()
+			}
+			v.Set(reflect.ValueOf(addr))
+
+			return true
+		},
+
+		// map[string]struct{} - add a new key to the set, values cannot be changed
+		func(v reflect.Value) bool {
+			m, ok := v.Interface().(map[string]struct{})
+			if !ok || m == nil {
+				return false
+			}
+
+			// Find the first absent key to get deterministic result
+			for i := len(m); ; i++ {
+				if key := fmt.Sprintf("added_key%d", i); !hasKey(m, key) {
+					m[key] = struct{}{}
+					break
+				}
+			}
+
+			return true
+		},
+
+		// []byte - increment the last byte or append one if empty
+		func(v reflect.Value) bool {
+			bs, ok := v.Interface().([]byte)
+			if !ok {
+				return false
+			}
+
+			if len(bs) == 0 {
+				v.Set(reflect.ValueOf(append(bs, initialSeed)))
+			} else {
+				bs[len(bs)-1]++
+			}
+
+			return true
+		},
+
+		// [][]byte - increment the last byte of the last inner slice, the inner
+		// buffer is modified in place to reveal buffers shared with the original
+		func(v reflect.Value) bool {
+			bss, ok := v.Interface().([][]byte)
+			if !ok {
+				return false
+			}
+
+			switch last := len(bss) - 1; {
+			case last < 0:
+				v.Set(reflect.ValueOf(append(bss, []byte{initialSeed})))
+			case len(bss[last]) == 0:
+				bss[last] = append(bss[last], initialSeed)
+			default:
+				bss[last][len(bss[last])-1]++
 			}
 
 			return true
 		},
 	}
 }
+
+// hasKey returns true if the set m contains the key
+func hasKey(m map[string]struct{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
@@ -0,0 +1,217 @@
+package clone
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSupportedTypes(t *testing.T) {
+	setters := EmbSetters()
+	changers := EmbChangers()
+
+	for _, typ := range SupportedTypes() {
+		// Create value by embedded setters
+		x, ok := trySetters(setters, reflect.New(typ).Elem())
+		if !ok {
+			t.Errorf("type %q is not supported by embedded setters", typ)
+			continue
+		}
+		if x.Type() != typ {
+			t.Errorf("embedded setter for type %q returned value of type %q", typ, x.Type())
+			continue
+		}
+
+		// Change a copy of the created value
+		v := reflect.New(typ).Elem()
+		v.Set(x)
+		if !tryChangers(changers, v) {
+			t.Errorf("type %q is not supported by embedded changers", typ)
+		}
+	}
+}
+
+type testAttrs struct {
+	Attrs	map[string]any
+}
+
+// cloneAttrs returns a clone of testAttrs, slice values of the map are shared
+// with the original unless deep is set
+func cloneAttrs(x any, deep bool) any {
+	orig := x.(*testAttrs)	//nolint:forcetypeassert
+	rv := &testAttrs{Attrs: make(map[string]any, len(orig.Attrs))}
+	for k, v := range orig.Attrs {
+		if deep {
+			switch s := v.(type) {
+			case []string:
+				v = append([]string(nil), s...)
+			case []int:
+				v = append([]int(nil), s...)
+			}
+		}
+		rv.Attrs[k] = v
+	}
+
+	return rv
+}
+
+func TestMapSliceValues(t *testing.T) {
+	// Without slice values the shared slices cannot be detected
+	sv := NewStructVerifier(
+		func() any { return &testAttrs{} },
+		func(x any) any { return cloneAttrs(x, false) },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of map with int values failed: %v", err)
+	}
+
+	sv = NewStructVerifierWith(
+		func() any { return &testAttrs{} },
+		func(x any) any { return cloneAttrs(x, true) },
+		WithMapSliceValues(),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of deep copied map with slice values failed: %v", err)
+	}
+}
+
+func TestMapSliceValuesShared(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testAttrs{} },
+		func(x any) any { return cloneAttrs(x, false) },
+		WithMapSliceValues(),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares slice values with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestMapSliceValuesChanger(t *testing.T) {
+	// Changing of values of other types must not panic
+	m := map[string]any{"a": 1.5, "b": []string{"x"}}
+	if !tryChangers(EmbChangers(), reflect.ValueOf(m)) {
+		t.Errorf("map with slice value was not changed")
+	}
+	if m["a"] != 1.5 || m["b"].([]string)[0] != "x_" {
+		t.Errorf("unexpected map after change: %v", m)
+	}
+
+	// Maps without values to change are still supported by the changer
+	for _, m := range []map[string]any{{}, {"a": 1.5}} {
+		if !tryChangers(EmbChangers(), reflect.ValueOf(m)) {
+			t.Errorf("map[string]any %v is not supported by embedded changers", m)
+		}
+	}
+}
+
+// cloneAnyMap returns a copy of m, nested maps are shared with m unless deep is set
+func cloneAnyMap(m map[string]any, deep bool) map[string]any {
+	rv := make(map[string]any, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]any); ok && deep {
+			v = cloneAnyMap(nested, deep)
+		}
+		rv[k] = v
+	}
+
+	return rv
+}
+
+func TestMapNestedValues(t *testing.T) {
+	// Without nested maps the shared nested maps cannot be detected
+	sv := NewStructVerifier(
+		func() any { return &testAttrs{} },
+		func(x any) any { return &testAttrs{Attrs: cloneAnyMap(x.(*testAttrs).Attrs, false)} },	//nolint:forcetypeassert
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of flat map failed: %v", err)
+	}
+
+	sv = NewStructVerifierWith(
+		func() any { return &testAttrs{} },
+		func(x any) any { return &testAttrs{Attrs: cloneAnyMap(x.(*testAttrs).Attrs, true)} },	//nolint:forcetypeassert
+		WithMapNestedValues(2),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of deep copied nested maps failed: %v", err)
+	}
+	x, _ := sv.SampleFilled()
+	nested, _ := x.(*testAttrs).Attrs[nestedMapKey].(map[string]any)	//nolint:forcetypeassert
+	if _, ok := nested[nestedMapKey].(map[string]any); !ok {
+		t.Errorf("map is not nested to depth 2: %#v", x)
+	}
+}
+
+func TestMapNestedValuesShared(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testAttrs{} },
+		func(x any) any { return &testAttrs{Attrs: cloneAnyMap(x.(*testAttrs).Attrs, false)} },	//nolint:forcetypeassert
+		WithMapNestedValues(2),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares nested maps with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		if !strings.Contains(err.Error(), `"Attrs[nested]`) {
+			t.Errorf("error does not contain the nested key path: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testMeasure struct {
+	Value	float64
+	Ratio	float32
+	Samples	[]float64
+}
+
+func cloneMeasure(x any) any {
+	rv := *x.(*testMeasure)	//nolint:forcetypeassert
+	rv.Samples = append([]float64(nil), rv.Samples...)
+	return &rv
+}
+
+func TestFloatValues(t *testing.T) {
+	// Finite values by default
+	sv := NewStructVerifier(func() any { return &testMeasure{} }, cloneMeasure)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of float fields failed: %v", err)
+	}
+	x, _ := sv.SampleFilled()
+	for _, f := range append([]float64{x.(*testMeasure).Value}, x.(*testMeasure).Samples...) {	//nolint:forcetypeassert
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			t.Errorf("non-finite value %v is filled by default: %#v", f, x)
+		}
+	}
+}
+
+func TestFloatValuesNonFinite(t *testing.T) {
+	// NaN values of the original and the reference must be equal
+	sv := NewStructVerifierWith(func() any { return &testMeasure{} }, cloneMeasure, WithNonFiniteFloats())
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of non-finite float fields failed: %v", err)
+	}
+	x, _ := sv.SampleFilled()
+	m := x.(*testMeasure)	//nolint:forcetypeassert
+	if !math.IsInf(m.Value, 1) || !math.IsInf(float64(m.Ratio), -1) || !math.IsNaN(m.Samples[0]) {
+		t.Errorf("unexpected non-finite values: %#v", m)
+	}
+}
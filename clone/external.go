@@ -0,0 +1,161 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// external holds the functions registered for the external structure type
+type external struct {
+	producer	ConcreteProducer
+	cloner		ClonerFunc
+}
+
+/*
+RegisterExternal registers the structure type t defined in another package,
+e.g. otherpkg.Settings, to participate in the verification of structures that
+contain it. Such types often cannot be filled automatically, because they have
+unexported fields. The producer creates values of type t (or pointers to them)
+to fill the fields of type t at any level of nesting, including the pointers,
+slices and maps of t. The argument n is the sequence number of the produced
+value, different positive values of n must produce different values.
+
+Values of type t are changed by the Changer functions through their exported
+fields. If there are no changeable fields, the value is replaced by the value
+produced for a negative n, so the sharing of unexported data of such values
+cannot be detected by the verification of the containing structure.
+
+If the cloner is not nil, it must create the clone of the pointer to t, e.g.
+by calling the Clone method of t. The cloner is verified by its own
+[StructVerifier] configured the same way as this one using the value produced
+for n = 1 as the original, see [StructVerifier.VerifyInstance]. If the
+verification fails, the *[ErrSVNestedClone] error with the name of the type
+as the path is returned:
+
+  sv.RegisterExternal(reflect.TypeOf(otherpkg.Settings{}),
+      func(n int) any { return otherpkg.NewSettings(fmt.Sprint("name_", n)) },
+      func(x any) any { return x.(*otherpkg.Settings).Clone() },
+  )
+
+Registering the type again replaces the previous functions.
+*/
+func (sv *StructVerifier) RegisterExternal(t reflect.Type, producer ConcreteProducer, cloner ClonerFunc) *StructVerifier {
+	if sv.externals == nil {
+		sv.externals = map[reflect.Type]external{}
+	}
+	sv.externals[t] = external{producer: producer, cloner: cloner}
+
+	return sv
+}
+
+// produceExternal calls the producer of the external type t for n and returns
+// the produced value of type t
+func (sv *StructVerifier) produceExternal(t reflect.Type, n int, path string) (reflect.Value, error) {
+	x := sv.externals[t].producer(n)
+
+	val := reflect.ValueOf(x)
+	switch {
+	case !val.IsValid():
+		return reflect.Value{}, fmt.Errorf("producer of the external type %q returned nil value for %q", t, path)
+	case val.Type() == reflect.PointerTo(t):
+		if val.IsNil() {
+			return reflect.Value{}, fmt.Errorf("producer of the external type %q returned nil pointer for %q", t, path)
+		}
+		return val.Elem(), nil
+	case val.Type() != t:
+		return reflect.Value{}, fmt.Errorf("producer of the external type %q returned value of type %q for %q",
+			t, val.Type(), path)
+	}
+
+	return val, nil
+}
+
+// externalValue creates the value of the registered external type. It returns
+// false if v is not of a registered external type
+func (fl *filler) externalValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	if _, ok := fl.sv.externals[v.Type()]; !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	// Sequence numbers start from 1 to avoid zero values
+	fl.state.seq++
+	val, err := fl.sv.produceExternal(v.Type(), fl.state.seq, path)
+
+	return val, true, err
+}
+
+// changeExternal changes the value of the registered external type through
+// its exported fields or replaces it by the value produced for a negative
+// sequence number. It returns false if v is not of a registered external type
+func (ch *changer) changeExternal(v reflect.Value) bool {
+	if _, ok := ch.sv.externals[v.Type()]; !ok {
+		return false
+	}
+
+	if ch.changeGeneric(v) {
+		return true
+	}
+
+	ch.extSeq--
+	val, err := ch.sv.produceExternal(v.Type(), ch.extSeq, "")
+	if err != nil || !v.CanSet() {
+		return false
+	}
+	v.Set(val)
+
+	return true
+}
+
+// verifyExternal verifies the cloners of the registered external types
+func (sv *StructVerifier) verifyExternal() error {
+	types := make([]reflect.Type, 0, len(sv.externals))
+	for t, ext := range sv.externals {
+		if ext.cloner != nil {
+			types = append(types, t)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+
+	for _, t := range types {
+		orig, err := sv.produceExternal(t, 1, t.String())
+		if err != nil {
+			return &ErrSVOrigFill{newErrSV("cannot produce original value: %w", err)}
+		}
+		p := reflect.New(t)
+		p.Elem().Set(orig)
+
+		st := t
+		esv := *sv
+		esv.creator = func() any { return reflect.New(st).Interface() }
+		esv.cloner = sv.externals[t].cloner
+		esv.nestedCloners = false
+		esv.externals = withoutCloners(sv.externals)
+		esv.accessors = nil
+		esv.sharedFields = nil
+		esv.onFieldStart, esv.onFieldDone = nil, nil
+
+		if err := esv.VerifyInstance(p.Interface()); err != nil {
+			return &ErrSVNestedClone{
+				structVerifierError:	newErrSV("verification of the cloner of the external" +
+											" type %v failed: %w", t, err),
+				Path:					t.String(),
+				Err:					err,
+			}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// withoutCloners returns a copy of externals without cloners, to fill and
+// change the fields of external types without verifying their cloners again
+func withoutCloners(externals map[reflect.Type]external) map[reflect.Type]external {
+	rv := make(map[reflect.Type]external, len(externals))
+	for t, ext := range externals {
+		rv[t] = external{producer: ext.producer}
+	}
+
+	return rv
+}
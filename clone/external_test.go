@@ -0,0 +1,96 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// testToken simulates a type of another package with unexported fields only
+type testToken struct {
+	id	int
+}
+
+// testQuota simulates a type of another package with unexported and exported fields
+type testQuota struct {
+	owner	string
+	Limits	[]int
+}
+
+type testAccount struct {
+	Name	string
+	Token	testToken
+	Quota	*testQuota
+}
+
+// cloneQuota returns a clone of testQuota, limits are shared with the original if shallow is set
+func cloneQuota(x any, shallow bool) any {
+	orig := x.(*testQuota)	//nolint:forcetypeassert
+	rv := &testQuota{owner: orig.owner, Limits: orig.Limits}
+	if !shallow {
+		rv.Limits = append([]int(nil), orig.Limits...)
+	}
+
+	return rv
+}
+
+// cloneAccount returns a clone of testAccount with the deep copy of the quota
+func cloneAccount(x any) any {
+	orig := x.(*testAccount)	//nolint:forcetypeassert
+	rv := *orig
+	if orig.Quota != nil {
+		rv.Quota = cloneQuota(orig.Quota, false).(*testQuota)	//nolint:forcetypeassert
+	}
+
+	return &rv
+}
+
+// produceQuota returns a quota of the n-th owner
+func produceQuota(n int) any {
+	return &testQuota{owner: fmt.Sprint("owner_", n), Limits: []int{n}}
+}
+
+func TestRegisterExternal(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testAccount{} },
+		cloneAccount,
+		WithExternal(reflect.TypeOf(testToken{}), func(n int) any { return testToken{id: n} }, nil),
+		WithExternal(reflect.TypeOf(testQuota{}), produceQuota, func(x any) any { return cloneQuota(x, false) }),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the clone with external types failed: %v", err)
+	}
+
+	x, err := sv.SampleFilled()
+	if err != nil {
+		t.Fatalf("cannot fill sample structure: %v", err)
+	}
+	if acc := x.(*testAccount); acc.Token.id == 0 || acc.Quota == nil || acc.Quota.owner == "" {	//nolint:forcetypeassert
+		t.Errorf("external types are not filled by producers: %#v", acc)
+	}
+}
+
+func TestRegisterExternalShallow(t *testing.T) {
+	// The cloner of the external type shares limits with the original
+	sv := NewStructVerifierWith(
+		func() any { return &testAccount{} },
+		cloneAccount,
+		WithExternal(reflect.TypeOf(testToken{}), func(n int) any { return testToken{id: n} }, nil),
+		WithExternal(reflect.TypeOf(testQuota{}), produceQuota, func(x any) any { return cloneQuota(x, true) }),
+	)
+
+	err := sv.Verify()
+	var nErr *ErrSVNestedClone
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the external cloner shares limits")
+	case errors.As(err, &nErr):
+		if nErr.Path != "clone.testQuota" {
+			t.Errorf("got path %q, want - %q", nErr.Path, "clone.testQuota")
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNestedClone", err, err)
+	}
+}
@@ -0,0 +1,54 @@
+package clone
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+VerifyExpectingFields works like [StructVerifier.Verify], but before the
+verification it checks that the set of the exported fields of the structure,
+which cloning is verified, is equal to want. It catches the structure changes
+that are not reflected in the test, e.g. a new field that is not copied by the
+Clone method, or a removed (or unexported) field.
+
+If the sets differ, the *[ErrSVFieldsMismatch] error that lists missing and
+unexpected fields is returned, the order of fields in want does not matter.
+The fields of structures embedded by value are expected by the same paths as
+they are verified, like "Inner.Items".
+*/
+func (sv *StructVerifier) VerifyExpectingFields(want []string) error {
+	got := structFields(sv.creator())
+
+	missing := fieldsDiff(want, got)
+	unexpected := fieldsDiff(got, want)
+	if missing != nil || unexpected != nil {
+		return &ErrSVFieldsMismatch{
+			structVerifierError:	newErrSV("verified fields differ from the expected:" +
+										" missing - [%s], unexpected - [%s]",
+										strings.Join(missing, ", "), strings.Join(unexpected, ", ")),
+			Missing:				missing,
+			Unexpected:				unexpected,
+		}
+	}
+
+	return sv.Verify()
+}
+
+// fieldsDiff returns the sorted list of fields from a that are absent in b
+func fieldsDiff(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, f := range b {
+		inB[f] = true
+	}
+
+	var diff []string
+	for _, f := range a {
+		if !inB[f] {
+			diff = append(diff, f)
+		}
+	}
+	sort.Strings(diff)
+
+	return diff
+}
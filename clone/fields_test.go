@@ -0,0 +1,45 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVerifyExpectingFields(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+	).AddSetters(intSliceSetter).AddChangers(intSliceChanger)
+
+	fields := []string{"MapVals", "Int64param", "IntList", "Int64List", "StringList"}
+	if err := sv.VerifyExpectingFields(fields); err != nil {
+		t.Errorf("verification with expected fields failed: %v", err)
+	}
+
+	err := sv.VerifyExpectingFields([]string{"Int64param", "IntList", "Int64List", "StringList", "Removed"})
+
+	var errFields *ErrSVFieldsMismatch
+	if !errors.As(err, &errFields) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVFieldsMismatch", err, err)
+	}
+	if want := []string{"Removed"}; !reflect.DeepEqual(errFields.Missing, want) {
+		t.Errorf("got missing fields %v, want - %v", errFields.Missing, want)
+	}
+	if want := []string{"MapVals"}; !reflect.DeepEqual(errFields.Unexpected, want) {
+		t.Errorf("got unexpected fields %v, want - %v", errFields.Unexpected, want)
+	}
+}
+
+func TestVerifyExpectingFieldsPromoted(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testEmbeddedOuter{} },
+		func(x any) any { return cloneEmbeddedOuter(x, false) },
+	)
+
+	// Fields of the embedded structure are expected by the same paths as verified
+	fields := []string{"testEmbeddedInner.Items", "testEmbeddedInner.Name", "Count"}
+	if err := sv.VerifyExpectingFields(fields); err != nil {
+		t.Errorf("verification with expected promoted fields failed: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// funcTypes caches results of the hasFunc function
+var funcTypes sync.Map // map[reflect.Type]bool
+
+// funcKey identifies the function filled into the func value
+type funcKey struct {
+	path	string
+	typ		reflect.Type
+}
+
+// funcCache holds the functions filled into func values, the same function
+// is used for the same path in all fillings, so the original and the
+// reference structures are equal
+type funcCache struct {
+	mu		sync.Mutex
+	funcs	map[funcKey]reflect.Value
+}
+
+// get returns the function of type t for the path, the function is created if
+// it does not exist. Created functions do nothing and return zero values
+func (fc *funcCache) get(path string, t reflect.Type) reflect.Value {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	key := funcKey{path, t}
+	if fn, ok := fc.funcs[key]; ok {
+		return fn
+	}
+
+	fn := reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+		return out
+	})
+	if fc.funcs == nil {
+		fc.funcs = map[funcKey]reflect.Value{}
+	}
+	fc.funcs[key] = fn
+
+	return fn
+}
+
+// funcValue returns the function for the func value v, each path gets its
+// own distinct non-nil function. It returns false if v is not a func value
+func (fl *filler) funcValue(v reflect.Value, path string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Func {
+		return reflect.Value{}, false
+	}
+
+	return fl.sv.funcs.get(path, v.Type()), true
+}
+
+// hasFunc returns true if values of type t may contain functions, such values
+// cannot be compared by reflect.DeepEqual, because non-nil functions are never equal
+func hasFunc(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	if v, ok := funcTypes.Load(t); ok {
+		return v.(bool) //nolint:forcetypeassert	// only bool values are stored
+	}
+
+	rv := findType(t, func(t reflect.Type) bool { return t.Kind() == reflect.Func }, map[reflect.Type]bool{})
+	funcTypes.Store(t, rv)
+
+	return rv
+}
+
+// funcEqual reports whether the func values a and b hold the same function.
+// Copying of the function value keeps it the same
+func funcEqual(a, b reflect.Value) bool {
+	if a.IsNil() || b.IsNil() {
+		return a.IsNil() && b.IsNil()
+	}
+
+	if !a.CanInterface() || !b.CanInterface() {
+		// Only code pointers are available for unexported values
+		return a.Pointer() == b.Pointer()
+	}
+
+	return funcPointer(a) == funcPointer(b)
+}
+
+// funcPointer returns the pointer to the closure of the function v, unlike the
+// code pointer it is different for different closures of the same code
+func funcPointer(v reflect.Value) unsafe.Pointer {
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+
+	return *(*unsafe.Pointer)(c.UnsafePointer())
+}
+
+// isFuncField reports whether the field of the verified structure has func type.
+// Functions cannot be deeply cloned, so such fields are only compared, not changed
+func (sv *StructVerifier) isFuncField(field string) bool {
+	f, ok := typeFieldByPath(reflect.TypeOf(sv.creator()).Elem(), field)
+	return ok && f.Type.Kind() == reflect.Func
+}
+
+// changeableFields returns the fields which can be changed during verification
+func (sv *StructVerifier) changeableFields(fields []string) []string {
+	var rv []string
+	for _, field := range fields {
+		if !sv.isShared(field) && !sv.isFuncField(field) && !sv.isEmptyArrayField(field) {
+			rv = append(rv, field)
+		}
+	}
+
+	return rv
+}
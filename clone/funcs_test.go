@@ -0,0 +1,58 @@
+package clone
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCallbacks struct {
+	Name		string
+	OnChange	func()
+	Validate	func(string) error
+}
+
+// cloneCallbacks returns a clone of testCallbacks, if dropFuncs is true,
+// the functions are not copied to the clone
+func cloneCallbacks(x any, dropFuncs bool) any {
+	orig := x.(*testCallbacks)	//nolint:forcetypeassert
+
+	rv := &testCallbacks{Name: orig.Name}
+	if !dropFuncs {
+		rv.OnChange = orig.OnChange
+		rv.Validate = orig.Validate
+	}
+
+	return rv
+}
+
+func TestCloneFuncFields(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testCallbacks{} },
+		func(x any) any { return cloneCallbacks(x, false) },
+	).ChangeAllFields().StrictSetters()
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of structure with func fields failed: %v", err)
+	}
+
+	filled, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill: %v", err)
+	}
+	cb := filled.(*testCallbacks)	//nolint:forcetypeassert
+	if cb.OnChange == nil || cb.Validate == nil || cb.Validate("x") != nil {
+		t.Errorf("func fields filled incorrectly: %#v", cb)
+	}
+
+	err = NewStructVerifier(
+		func() any { return &testCallbacks{} },
+		func(x any) any { return cloneCallbacks(x, true) },
+	).Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the clone drops functions")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+}
@@ -0,0 +1,518 @@
+package clone
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"sync"
+)
+
+const (
+	// defaultMaxDepth is the default maximum pointer indirection depth
+	defaultMaxDepth = 8
+	// genericLen is the number of elements of automatically filled slices and maps
+	genericLen = 3
+	// recursionLimit is the number of nested values of the same pointer type
+	// filled for recursive types, deeper pointers of this type are left nil
+	recursionLimit = 2
+)
+
+// filler holds the state of a single filling pass of the structure: the
+// instantiated Setter functions and the seed state of embedded setters and
+// produced concrete values. All fields of the structure must be filled by the same filler
+// to get different values for different fields of the same type
+type filler struct {
+	sv			*StructVerifier
+	fSetters	[]SetterWithField	// user defined setters taking field names
+	uSetters	[]Setter			// user defined setters
+	eSetters	[]Setter			// embedded setters
+	state		*SeedState			// current state of embedded setters and concrete values
+	ptrDepth	int					// current pointer indirection depth
+	recursive	map[reflect.Type]int	// pointer types being filled on the current path
+}
+
+// newFiller creates a new filler with refreshed initial values of setters
+func (sv *StructVerifier) newFiller() *filler {
+	// Create new user defined setters to refresh initial values
+	uSetters := make([]Setter, 0, len(sv.setters))
+	for _, mkSetter := range sv.setters {
+		uSetters = append(uSetters, mkSetter())
+	}
+
+	fSetters := make([]SetterWithField, 0, len(sv.fieldSetters))
+	for _, mkSetter := range sv.fieldSetters {
+		fSetters = append(fSetters, mkSetter())
+	}
+
+	// Each filling starts from the same state
+	state := sv.state
+
+	eSetters := state.setters()
+	if sv.mapSlices {
+		// Should be placed before the embedded map[string]any setter
+		eSetters = append([]Setter{state.mapSliceSetter()}, eSetters...)
+	}
+	if sv.mapNestDepth > 0 {
+		// Nested maps are produced by the map[string]any setters placed after it
+		eSetters = append([]Setter{state.nestedMapSetter(sv.mapNestDepth, eSetters)}, eSetters...)
+	}
+	if sv.nonFinite {
+		// Should be placed before the embedded float setters
+		eSetters = append([]Setter{state.nonFiniteSetter()}, eSetters...)
+	}
+
+	return &filler{
+		sv:			sv,
+		fSetters:	fSetters,
+		uSetters:	uSetters,
+		eSetters:	eSetters,
+		state:		&state,
+		recursive:	map[reflect.Type]int{},
+	}
+}
+
+// Names of the handlers that fill values, see filler.fill
+const (
+	handlerFieldSetter	=	"field setter"
+	handlerSetter		=	"setter"
+	handlerConcrete		=	"concrete"
+	handlerExternal		=	"external"
+	handlerEmbedded		=	"embedded"
+	handlerNamed		=	"named"
+	handlerAtomic		=	"atomic"
+	handlerSyncMap		=	"sync.Map"
+	handlerFunc			=	"func"
+	handlerGeneric		=	"generic"
+)
+
+// value returns a new value appropriate to set to v. The path is the path
+// to the value from the structure root, it is used in error messages
+func (fl *filler) value(v reflect.Value, path string) (reflect.Value, error) {
+	x, _, err := fl.fill(v, path)
+	return x, err
+}
+
+// fill works like value, but also returns the name of the handler that
+// created the value, e.g. "embedded" for the embedded setters
+func (fl *filler) fill(v reflect.Value, path string) (reflect.Value, string, error) {
+	// Try to create value using user defined setters taking field names
+	for _, setter := range fl.fSetters {
+		if x := setter(path, v); x != nil {
+			return reflect.ValueOf(x), handlerFieldSetter, checkSetterType(reflect.ValueOf(x), v, path)
+		}
+	}
+
+	// Try to create value using user defined setters
+	if x, ok := trySetters(fl.uSetters, v); ok {
+		return x, handlerSetter, checkSetterType(x, v, path)
+	}
+
+	// Try to use registered concrete values for interface types
+	if x, ok, err := fl.concreteValue(v, path); ok || err != nil {
+		return x, handlerConcrete, err
+	}
+
+	// Try to use registered producers of external types
+	if x, ok, err := fl.externalValue(v, path); ok || err != nil {
+		return x, handlerExternal, err
+	}
+
+	// Try embedded setters
+	if x, ok := trySetters(fl.eSetters, v); ok {
+		return x, handlerEmbedded, checkSetterType(x, v, path)
+	}
+
+	// Try to fill named types using setters of their underlying types
+	if x, ok, err := fl.namedValue(v, path); ok || err != nil {
+		return x, handlerNamed, err
+	}
+
+	// Try to fill atomic wrappers, they cannot be filled as regular structures
+	if x, ok, err := fl.atomicValue(v, path); ok || err != nil {
+		return x, handlerAtomic, err
+	}
+
+	// Try to fill sync.Map values, they cannot be filled as regular structures
+	if x, ok, err := fl.syncMapValue(v, path); ok || err != nil {
+		return x, handlerSyncMap, err
+	}
+
+	// Try to fill functions
+	if x, ok := fl.funcValue(v, path); ok {
+		return x, handlerFunc, nil
+	}
+
+	// Try to fill pointers, structures, slices and maps
+	if x, ok, err := fl.genericValue(v, path); ok || err != nil {
+		return x, handlerGeneric, err
+	}
+
+	// Interface values can be produced only by registered producers
+	if v.Kind() == reflect.Interface {
+		return reflect.Value{}, "", fmt.Errorf("field %q has interface type %q without registered concrete" +
+			" producers, see StructVerifier.RegisterConcrete", path, v.Type())
+	}
+
+	// No suitable setter - unsupported type of field
+	return reflect.Value{}, "", fmt.Errorf("field %q has unsupported type to set - %q", path, v.Type())
+}
+
+// checkSetterType checks that the value x returned by a setter can be assigned to v
+func checkSetterType(x, v reflect.Value, path string) error {
+	if x.Type().AssignableTo(v.Type()) {
+		return nil
+	}
+
+	return &ErrSVSetterTypeMismatch{newErrSV("setter returned value of type %q for field %q of type %q",
+		x.Type(), path, v.Type())}
+}
+
+// trySetters returns the value created by the first setter that supports
+// the type of v, or false if there is no such setter
+func trySetters(setters []Setter, v reflect.Value) (reflect.Value, bool) {
+	for _, setter := range setters {
+		if x := setter(v); x != nil {
+			return reflect.ValueOf(x), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// namedValue creates values for named types (like type IDs []int64) using the
+// setters of the unnamed type with the same underlying type. It returns false
+// if v is not a value of named type or its kind is not supported
+func (fl *filler) namedValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	ut, ok := unnamedType(v.Type())
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	val, err := fl.value(reflect.New(ut).Elem(), path)
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	// Convert value to the named type
+	return val.Convert(v.Type()), true, nil
+}
+
+// genericValue creates values for pointers by allocating the pointed value
+// and filling it, for structures by filling their exported fields, for slices
+// and maps by filling their elements. It returns false if the kind of v is
+// not supported
+func (fl *filler) genericValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	switch v.Kind() { //nolint:exhaustive	// other kinds are not supported
+	case reflect.Pointer:
+		if fl.ptrDepth >= fl.sv.maxDepth {
+			return reflect.Value{}, true, fmt.Errorf("field %q exceeds the maximum pointer indirection depth %d",
+				path, fl.sv.maxDepth)
+		}
+
+		// Recursive types, e.g. tree nodes with pointers to children, are
+		// filled to a limited number of levels to terminate the recursion
+		if fl.recursive[v.Type()] >= recursionLimit {
+			return reflect.Zero(v.Type()), true, nil
+		}
+
+		fl.ptrDepth++
+		fl.recursive[v.Type()]++
+		defer func() { fl.ptrDepth--; fl.recursive[v.Type()]-- }()
+
+		// Allocate memory for the pointed value and fill it
+		ptr := reflect.New(v.Type().Elem())
+		val, err := fl.value(ptr.Elem(), path)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		ptr.Elem().Set(val)
+
+		return ptr, true, nil
+
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		nSet := 0
+		for i := 0; i < s.NumField(); i++ {
+			name := s.Type().Field(i).Name
+			if !verifiable(s.Type().Field(i)) {
+				// Skip unexported field or lock
+				continue
+			}
+
+			val, err := fl.value(s.Field(i), path + "." + name)
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			s.Field(i).Set(val)
+			nSet++
+		}
+
+		if nSet == 0 {
+			return reflect.Value{}, true, fmt.Errorf("field %q has type %q without exported fields to set",
+				path, v.Type())
+		}
+
+		return s, true, nil
+
+	case reflect.Array:
+		// Fill each element by a distinct value, arrays of zero length stay empty
+		a := reflect.New(v.Type()).Elem()
+		for i := 0; i < a.Len(); i++ {
+			val, err := fl.value(a.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			a.Index(i).Set(val)
+		}
+
+		return a, true, nil
+
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 0, genericLen)
+		for i := 0; i < genericLen; i++ {
+			val, err := fl.value(reflect.New(v.Type().Elem()).Elem(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			s = reflect.Append(s, val)
+		}
+
+		return s, true, nil
+
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(v.Type(), genericLen)
+		for i := 0; i < genericLen; i++ {
+			key, err := fl.value(reflect.New(v.Type().Key()).Elem(), fmt.Sprintf("%s(key #%d)", path, i))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			val, err := fl.value(reflect.New(v.Type().Elem()).Elem(), fmt.Sprintf("%s[%v]", path, key))
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			m.SetMapIndex(key, val)
+		}
+
+		return m, true, nil
+
+	default:
+		return reflect.Value{}, false, nil
+	}
+}
+
+// basicTypes contains unnamed types for supported kinds of named types
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:		reflect.TypeOf(false),
+	reflect.Int:		reflect.TypeOf(int(0)),
+	reflect.Int8:		reflect.TypeOf(int8(0)),
+	reflect.Int16:		reflect.TypeOf(int16(0)),
+	reflect.Int32:		reflect.TypeOf(int32(0)),
+	reflect.Int64:		reflect.TypeOf(int64(0)),
+	reflect.Uint:		reflect.TypeOf(uint(0)),
+	reflect.Uint8:		reflect.TypeOf(uint8(0)),
+	reflect.Uint16:		reflect.TypeOf(uint16(0)),
+	reflect.Uint32:		reflect.TypeOf(uint32(0)),
+	reflect.Uint64:		reflect.TypeOf(uint64(0)),
+	reflect.Float32:	reflect.TypeOf(float32(0)),
+	reflect.Float64:	reflect.TypeOf(float64(0)),
+	reflect.String:		reflect.TypeOf(""),
+}
+
+// unnamedType returns the unnamed type with the same underlying type as the
+// named type t. It returns false if t is not a named type or its kind is not
+// supported
+func unnamedType(t reflect.Type) (reflect.Type, bool) {
+	if t.PkgPath() == "" {
+		// Predeclared or unnamed type
+		return nil, false
+	}
+
+	switch t.Kind() { //nolint:exhaustive	// other kinds are not supported
+	case reflect.Slice:
+		return reflect.SliceOf(t.Elem()), true
+	case reflect.Map:
+		return reflect.MapOf(t.Key(), t.Elem()), true
+	default:
+		ut, ok := basicTypes[t.Kind()]
+		return ut, ok
+	}
+}
+
+// isExported reports whether the field name is exported, i.e. it begins
+// with an upper-case letter, including non-ASCII letters
+func isExported(name string) bool {
+	return token.IsExported(name)
+}
+
+// lockTypes contains types of locks, fields of these types are not verified
+var lockTypes = map[reflect.Type]bool{
+	reflect.TypeOf((*sync.Mutex)(nil)).Elem():		true,
+	reflect.TypeOf((*sync.RWMutex)(nil)).Elem():	true,
+}
+
+// verifiable reports whether the field f can be filled, changed and verified,
+// i.e. it is exported and is not a lock, such as sync.Mutex or sync.RWMutex.
+// Locks cannot be cloned meaningfully, so they are skipped and never locked
+func verifiable(f reflect.StructField) bool {
+	return isExported(f.Name) && !lockTypes[f.Type]
+}
+
+// isEmptyArrayField reports whether the field of the verified structure is
+// an array of zero length, such fields hold nothing to change and verify
+func (sv *StructVerifier) isEmptyArrayField(field string) bool {
+	f, ok := typeFieldByPath(reflect.TypeOf(sv.creator()).Elem(), field)
+	return ok && f.Type.Kind() == reflect.Array && f.Type.Len() == 0
+}
+
+// changer holds the set of Changer functions used to change the field values
+type changer struct {
+	sv			*StructVerifier
+	uChangers	[]Changer			// user defined changers
+	eChangers	[]Changer			// embedded changers
+	ptrDepth	int					// current pointer indirection depth
+	visited		map[uintptr]bool	// pointers already passed, to stop on cycles
+	oneEntry	bool				// change only one entry of maps
+	extSeq		int					// sequence number of replacing values of external types
+}
+
+// newChanger creates a new changer
+func (sv *StructVerifier) newChanger() *changer {
+	return &changer{
+		sv:			sv,
+		uChangers:	sv.changers,
+		eChangers:	embChangers(!sv.mapOneEntry),
+		visited:	map[uintptr]bool{},
+		oneEntry:	sv.mapOneEntry,
+	}
+}
+
+// change changes the value of v, it returns false if the type of v is not supported
+func (ch *changer) change(v reflect.Value) bool {
+	// Try to change value using user defined changers
+	if tryChangers(ch.uChangers, v) {
+		return true
+	}
+
+	// Try to change values stored in the interface types with registered concrete types
+	if ch.changeConcrete(v) {
+		return true
+	}
+
+	// Try to change values of registered external types
+	if ch.changeExternal(v) {
+		return true
+	}
+
+	// Try embedded changers
+	if tryChangers(ch.eChangers, v) {
+		return true
+	}
+
+	// Try to change named types using changers of their underlying types
+	if ch.changeNamed(v) {
+		return true
+	}
+
+	// Try to change values stored in atomic wrappers
+	if ch.changeAtomic(v) {
+		return true
+	}
+
+	// Try to change entries of sync.Map values
+	if ch.changeSyncMap(v) {
+		return true
+	}
+
+	// Try to change pointed values, structure fields, elements of slices and maps
+	return ch.changeGeneric(v)
+}
+
+// changeNamed changes the value of named type using changers of the unnamed
+// type with the same underlying type. It returns false if v is not a value
+// of named type or its kind is not supported
+func (ch *changer) changeNamed(v reflect.Value) bool {
+	ut, ok := unnamedType(v.Type())
+	if !ok {
+		return false
+	}
+
+	// Change the converted copy of the value, slices and maps
+	// share their content with the original value
+	val := reflect.New(ut).Elem()
+	val.Set(v.Convert(ut))
+	if !ch.change(val) {
+		return false
+	}
+	v.Set(val.Convert(v.Type()))
+
+	return true
+}
+
+// changeGeneric changes the value pointed by v, all exported fields of the
+// structure v, the last element of the slice v or all values of the map v
+// (only the first changeable value in sorted order of keys if oneEntry is set).
+// It returns false if the kind of v is not supported
+func (ch *changer) changeGeneric(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive	// other kinds are not supported
+	case reflect.Pointer:
+		if v.IsNil() || ch.ptrDepth >= ch.sv.maxDepth || ch.visited[v.Pointer()] {
+			return false
+		}
+
+		// Values reachable by several paths, e.g. by back-references of
+		// cyclic structures, are changed only once
+		ch.visited[v.Pointer()] = true
+
+		ch.ptrDepth++
+		defer func() { ch.ptrDepth-- }()
+
+		// Change the innermost pointed value
+		return ch.change(v.Elem())
+
+	case reflect.Struct:
+		changed := false
+		for i := 0; i < v.NumField(); i++ {
+			if verifiable(v.Type().Field(i)) && ch.change(v.Field(i)) {
+				changed = true
+			}
+		}
+		return changed
+
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return false
+		}
+		return ch.change(v.Index(v.Len() - 1))
+
+	case reflect.Map:
+		changed := false
+		for _, key := range sortedKeys(v) {
+			// Map values are not addressable, need to make a copy
+			val := reflect.New(v.Type().Elem()).Elem()
+			val.Set(v.MapIndex(key))
+			if ch.change(val) {
+				v.SetMapIndex(key, val)
+				changed = true
+			}
+			if changed && ch.oneEntry {
+				break
+			}
+		}
+		return changed
+
+	default:
+		return false
+	}
+}
+
+// tryChangers applies changers to v one by one until v is changed, it returns
+// false if there is no changer that supports the type of v
+func tryChangers(changers []Changer, v reflect.Value) bool {
+	for _, changer := range changers {
+		if changer(v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,439 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testPtrInner struct {
+	Vals	[]int
+	N		int64
+}
+
+type testPtrStruct struct {
+	P		*int
+	PP		**int
+	Inner	*testPtrInner
+}
+
+// clonePtrStruct returns a clone of testPtrStruct, if sharePP is true,
+// the second level pointer of the PP field is shared with the original
+func clonePtrStruct(x any, sharePP bool) any {
+	orig, ok := x.(*testPtrStruct)
+	if !ok {
+		panic(fmt.Sprintf("unsupported type to clone - %T, want - *testPtrStruct", x))
+	}
+
+	rv := *orig
+
+	p := *orig.P
+	rv.P = &p
+
+	if sharePP {
+		pp := *orig.PP
+		rv.PP = &pp
+	} else {
+		p := **orig.PP
+		pp := &p
+		rv.PP = &pp
+	}
+
+	inner := *orig.Inner
+	inner.Vals = make([]int, len(orig.Inner.Vals))
+	copy(inner.Vals, orig.Inner.Vals)
+	rv.Inner = &inner
+
+	return &rv
+}
+
+func TestClonePointers(t *testing.T) {
+	if err := NewStructVerifier(
+		func() any { return &testPtrStruct{} },
+		func(x any) any { return clonePtrStruct(x, false) },
+	).Verify(); err != nil {
+		t.Errorf("verification of structure with pointers failed: %v", err)
+	}
+}
+
+func TestClonePointersShared(t *testing.T) {
+	err := NewStructVerifier(
+		func() any { return &testPtrStruct{} },
+		func(x any) any { return clonePtrStruct(x, true) },
+	).Verify()
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the second level pointer")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestClonePointersMaxDepth(t *testing.T) {
+	type deepStruct struct {
+		P	*********int
+	}
+
+	err := NewStructVerifier(
+		func() any { return &deepStruct{} },
+		func(x any) any { return x },
+	).Verify()
+
+	if !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
+type (
+	testTags	map[string]string
+	testIDs		[]int64
+	testName	string
+	testLevel	int
+)
+
+type testNamedStruct struct {
+	Tags	testTags
+	IDs		testIDs
+	Name	testName
+	Level	testLevel
+	Levels	[]testLevel
+}
+
+func cloneNamedStruct(x any, shareTags bool) any {
+	orig, ok := x.(*testNamedStruct)
+	if !ok {
+		panic(fmt.Sprintf("unsupported type to clone - %T, want - *testNamedStruct", x))
+	}
+
+	rv := *orig
+	if !shareTags {
+		rv.Tags = make(testTags, len(orig.Tags))
+		for k, v := range orig.Tags {
+			rv.Tags[k] = v
+		}
+	}
+	rv.IDs = make(testIDs, len(orig.IDs))
+	copy(rv.IDs, orig.IDs)
+	rv.Levels = make([]testLevel, len(orig.Levels))
+	copy(rv.Levels, orig.Levels)
+
+	return &rv
+}
+
+func TestCloneNamedTypes(t *testing.T) {
+	if err := NewStructVerifier(
+		func() any { return &testNamedStruct{} },
+		func(x any) any { return cloneNamedStruct(x, false) },
+	).StrictSetters().Verify(); err != nil {
+		t.Errorf("verification of structure with named types failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &testNamedStruct{} },
+		func(x any) any { return cloneNamedStruct(x, true) },
+	).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testGroups struct {
+	Groups	map[string][]int
+}
+
+// cloneGroupsShareLast returns a clone of testGroups that shares
+// only the slice with the last key in sorted order with the original
+func cloneGroupsShareLast(x any) any {
+	orig := x.(*testGroups)	//nolint:forcetypeassert
+
+	last := ""
+	for k := range orig.Groups {
+		if k > last {
+			last = k
+		}
+	}
+
+	rv := &testGroups{Groups: make(map[string][]int, len(orig.Groups))}
+	for k, v := range orig.Groups {
+		if k == last {
+			rv.Groups[k] = v
+		} else {
+			rv.Groups[k] = append([]int(nil), v...)
+		}
+	}
+
+	return rv
+}
+
+func TestMapChangeAllEntries(t *testing.T) {
+	// All entries are changed by default, so the shared entry is always detected
+	err := NewStructVerifier(func() any { return &testGroups{} }, cloneGroupsShareLast).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	// Only the first entry is changed, the shared entry is missed
+	if err := NewStructVerifierWith(func() any { return &testGroups{} }, cloneGroupsShareLast,
+		WithMapChangeAllEntries(false),
+	).Verify(); err != nil {
+		t.Errorf("changing of the first entry only must miss the shared entry, got: %v", err)
+	}
+}
+
+type testPtrSlices struct {
+	Ints	[]*int
+	Names	[]*string
+}
+
+// clonePtrSlices returns a clone of testPtrSlices, the new slices hold copies
+// of pointed values unless shareElems is set, the slices themselves are
+// shared if shareSlices is set
+func clonePtrSlices(x any, shareElems, shareSlices bool) any {
+	orig := x.(*testPtrSlices)	//nolint:forcetypeassert
+	if shareSlices {
+		rv := *orig
+		return &rv
+	}
+
+	rv := &testPtrSlices{}
+	for _, p := range orig.Ints {
+		if !shareElems {
+			v := *p
+			p = &v
+		}
+		rv.Ints = append(rv.Ints, p)
+	}
+	for _, p := range orig.Names {
+		if !shareElems {
+			v := *p
+			p = &v
+		}
+		rv.Names = append(rv.Names, p)
+	}
+
+	return rv
+}
+
+func TestClonePointerSlices(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testPtrSlices{} },
+		func(x any) any { return clonePtrSlices(x, false, false) },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of slices of pointers failed: %v", err)
+	}
+}
+
+func TestClonePointerSlicesSharedElems(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testPtrSlices{} },
+		func(x any) any { return clonePtrSlices(x, true, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares pointed elements with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestClonePointerSlicesShared(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testPtrSlices{} },
+		func(x any) any { return clonePtrSlices(x, false, true) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares slices with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testItem struct {
+	Tags	[]string
+	Count	int
+}
+
+type testInventory struct {
+	Items	map[string]testItem
+}
+
+// cloneInventory returns a clone of testInventory, the new map holds copies of
+// the items, inner slices of items are shared with the original unless deep is set
+func cloneInventory(x any, deep bool) any {
+	orig := x.(*testInventory)	//nolint:forcetypeassert
+	rv := &testInventory{Items: make(map[string]testItem, len(orig.Items))}
+	for k, item := range orig.Items {
+		if deep {
+			item.Tags = append([]string(nil), item.Tags...)
+		}
+		rv.Items[k] = item
+	}
+
+	return rv
+}
+
+func TestCloneStructMapValues(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testInventory{} },
+		func(x any) any { return cloneInventory(x, true) },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of map with structure values failed: %v", err)
+	}
+}
+
+func TestCloneStructMapValuesShared(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testInventory{} },
+		func(x any) any { return cloneInventory(x, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares inner slices of items")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// The first key in sorted order is reported
+		if want := `at "Items[b_1].Tags[1]"`; !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain the path of the shared slice %s", err, want)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testGrid struct {
+	Rows	[3][]int
+	None	[0][]int
+	Size	int
+}
+
+// cloneGrid returns a clone of testGrid, inner slices of rows are shared
+// with the original unless deep is set
+func cloneGrid(x any, deep bool) any {
+	orig := x.(*testGrid)	//nolint:forcetypeassert
+	rv := *orig
+	for i := 0; deep && i < len(rv.Rows); i++ {
+		rv.Rows[i] = append([]int(nil), orig.Rows[i]...)
+	}
+	return &rv
+}
+
+func TestCloneArrayOfSlices(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testGrid{} },
+		func(x any) any { return cloneGrid(x, true) },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of array of slices failed: %v", err)
+	}
+}
+
+func TestCloneArrayOfSlicesShared(t *testing.T) {
+	// Copying of the array copies only headers of inner slices
+	sv := NewStructVerifier(
+		func() any { return &testGrid{} },
+		func(x any) any { return cloneGrid(x, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares inner slices of the array")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// The last element of the last inner slice is changed
+		if want := `at "Rows[2][5]"`; !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain the path of the shared element %s", err, want)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testTreeNode struct {
+	Name		string
+	Children	[]*testTreeNode
+	Parent		*testTreeNode
+}
+
+// cloneTreeNode returns a clone of the graph of nodes, children are shared with
+// the original if shareChildren is set
+func cloneTreeNode(n *testTreeNode, shareChildren bool, seen map[*testTreeNode]*testTreeNode) *testTreeNode {
+	if n == nil {
+		return nil
+	}
+	if c, ok := seen[n]; ok {
+		return c
+	}
+
+	c := &testTreeNode{Name: n.Name}
+	seen[n] = c
+	if n.Children != nil {
+		c.Children = make([]*testTreeNode, len(n.Children))
+	}
+	for i, child := range n.Children {
+		if shareChildren {
+			c.Children[i] = child
+		} else {
+			c.Children[i] = cloneTreeNode(child, false, seen)
+		}
+	}
+	c.Parent = cloneTreeNode(n.Parent, shareChildren, seen)
+
+	return c
+}
+
+func TestCloneRecursiveTypes(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testTreeNode{} },
+		func(x any) any { return cloneTreeNode(x.(*testTreeNode), false, map[*testTreeNode]*testTreeNode{}) },	//nolint:forcetypeassert
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the tree failed: %v", err)
+	}
+
+	// The tree with back-references to the parent nodes
+	root := &testTreeNode{Name: "root", Parent: &testTreeNode{Name: "top"}}
+	root.Parent.Children = []*testTreeNode{root}
+	for _, name := range []string{"a", "b", "c"} {
+		root.Children = append(root.Children, &testTreeNode{Name: name, Parent: root})
+	}
+	if err := sv.VerifyInstance(root); err != nil {
+		t.Errorf("verification of the cyclic tree failed: %v", err)
+	}
+}
+
+func TestCloneRecursiveTypesShared(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testTreeNode{} },
+		func(x any) any { return cloneTreeNode(x.(*testTreeNode), true, map[*testTreeNode]*testTreeNode{}) },	//nolint:forcetypeassert
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares children with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
@@ -0,0 +1,444 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// concurrentIters is the number of changes and reads of each field performed
+// by goroutines of VerifyConcurrent
+const concurrentIters = 10
+
+// ErrSVAggregate represents a set of errors collected during the verification
+// of several structure types, see [VerifyWithCloner]. Each item of Errs
+// describes the failed verification of a single type.
+type ErrSVAggregate struct {
+	Errs	[]error
+}
+
+func (ea *ErrSVAggregate) Error() string {
+	msgs := make([]string, 0, len(ea.Errs))
+	for _, err := range ea.Errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("verification failed for %d type(s): %s", len(ea.Errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the list of collected errors, it is used by [errors.Is] and
+// [errors.As] since Go 1.20.
+func (ea *ErrSVAggregate) Unwrap() []error {
+	return ea.Errs
+}
+
+// Is reports whether any of the collected errors matches target, it allows to
+// use [errors.Is] on the aggregated error with Go versions before 1.20.
+func (ea *ErrSVAggregate) Is(target error) bool {
+	for _, err := range ea.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the collected errors that matches target, it allows to
+// use [errors.As] on the aggregated error with Go versions before 1.20.
+func (ea *ErrSVAggregate) As(target any) bool {
+	for _, err := range ea.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+VerifyWithCloner verifies that the single cloner function works properly for
+all types produced by the creator functions passed in creators. It is useful
+when the clone is produced by a generic deep-copy function shared between many
+types instead of a hand-written Clone method of each type:
+
+  err := clone.VerifyWithCloner(deepcopy.Copy,
+      func() any { return &Config{} },
+      func() any { return &Server{} },
+  )
+
+Each creator is verified by its own [StructVerifier], the verification of the
+remaining types continues after a failure. If any verification fails, an
+*[ErrSVAggregate] containing the errors of all failed types is returned.
+*/
+func VerifyWithCloner(cloner ClonerFunc, creators ...CreatorFunc) error {
+	var errs []error
+
+	for i, creator := range creators {
+		if err := NewStructVerifier(creator, cloner).Verify(); err != nil {
+			errs = append(errs, fmt.Errorf("creator #%d (%T): %w", i, creator(), err))
+		}
+	}
+
+	if errs != nil {
+		return &ErrSVAggregate{Errs: errs}
+	}
+
+	// OK
+	return nil
+}
+
+// ErrSVClonersDiverge represents an error that occurs when two cloner functions
+// produce different clones of the same original, see [CompareCloners]. Path
+// contains the path to the first differing element, like "Rows[2]".
+type ErrSVClonersDiverge struct {
+	structVerifierError
+	Path	string
+}
+
+/*
+CompareCloners verifies two cloner functions of the same type, e.g. the old and
+the new implementation of a refactored Clone method. Each cloner is verified
+by its own [StructVerifier] configured by the options, the error of the failed
+one is returned wrapped with the "first cloner" or "second cloner" prefix.
+
+If both cloners pass the verification, they are called for the same filled
+original and the produced clones must be deeply equal, as by [reflect.DeepEqual],
+regardless of the comparison options. Otherwise, the *[ErrSVClonersDiverge]
+error with the path to the first difference is returned:
+
+  err := clone.CompareCloners(creator, oldClone, newClone)
+*/
+func CompareCloners(creator CreatorFunc, first, second ClonerFunc, opts ...Option) error {
+	for _, cloner := range []struct {
+		name	string
+		fn		ClonerFunc
+	}{
+		{"first", first},
+		{"second", second},
+	} {
+		if err := NewStructVerifierWith(creator, cloner.fn, opts...).Verify(); err != nil {
+			return fmt.Errorf("%s cloner: %w", cloner.name, err)
+		}
+	}
+
+	sv := NewStructVerifierWith(creator, first, opts...)
+	orig, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	c1, err := sv.callCloner(orig, "")
+	if err != nil {
+		return fmt.Errorf("first cloner: %w", err)
+	}
+	sv.cloner = second
+	c2, err := sv.callCloner(orig, "")
+	if err != nil {
+		return fmt.Errorf("second cloner: %w", err)
+	}
+
+	// Clones are compared strictly, without user-defined comparison settings
+	strict := NewStructVerifier(creator, first)
+	if !strict.equal(c1, c2) {
+		path := strict.diffPath(reflect.ValueOf(c1).Elem(), reflect.ValueOf(c2).Elem(), "", 0)
+		return &ErrSVClonersDiverge{
+			structVerifierError:	newErrSV("clones produced by the FIRST (%s) and the SECOND (%s) cloners" +
+										" DIVERGE at %q", sv.dump(c1), sv.dump(c2), path),
+			Path:					path,
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+VerifyReceiverCloners verifies both clone entry points of types that define
+Clone on the value receiver and a separate clone method on the pointer
+receiver, e.g. ClonePtr. Each cloner adapts the corresponding method:
+
+  err := clone.VerifyReceiverCloners(func() any { return &Config{} },
+      func(x any) any { c := x.(*Config).Clone(); return &c },
+      func(x any) any { return x.(*Config).ClonePtr() },
+  )
+
+Each cloner is verified by its own [StructVerifier] configured by the options,
+the error of the failed one is returned wrapped with the "value receiver cloner"
+or "pointer receiver cloner" prefix. The pointer receiver cloner is not verified
+if the value receiver cloner fails.
+*/
+func VerifyReceiverCloners(creator CreatorFunc, valueCloner, ptrCloner ClonerFunc, opts ...Option) error {
+	for _, cloner := range []struct {
+		name	string
+		fn		ClonerFunc
+	}{
+		{"value receiver", valueCloner},
+		{"pointer receiver", ptrCloner},
+	} {
+		if err := NewStructVerifierWith(creator, cloner.fn, opts...).Verify(); err != nil {
+			return fmt.Errorf("%s cloner: %w", cloner.name, err)
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+VerifyGeneric verifies the clone function of the structure type T. It is a
+type-safe shortcut for [NewStructVerifierWith], that does not require writing
+creator and cloner functions operating on the any type. It is especially
+useful to verify the Clone method of generic types for several instantiations:
+
+  type Container[T any] struct {
+      Items []T
+  }
+
+  func (c *Container[T]) Clone() *Container[T] { ... }
+
+  err := clone.VerifyGeneric(
+      func() *Container[int] { return &Container[int]{} },
+      (*Container[int]).Clone,
+  )
+  ...
+  err = clone.VerifyGeneric(
+      func() *Container[string] { return &Container[string]{} },
+      (*Container[string]).Clone,
+  )
+
+Fields of the instantiated generic type have concrete types (e.g. []int for
+[]T where T is int), so they are handled by the Setter and Changer functions
+of the concrete types.
+*/
+func VerifyGeneric[T any](create func() *T, clone func(*T) *T, opts ...Option) error {
+	return NewStructVerifierWith(
+		func() any { return create() },
+		func(x any) any {
+			v, ok := x.(*T)
+			if !ok {
+				panic(fmt.Sprintf("unsupported type to clone: got - %T, want - %T", x, v))
+			}
+			return clone(v)
+		},
+		opts...,
+	).Verify()
+}
+
+// GenericCase is a single instantiation of a generic type to be verified by
+// [VerifyGenericCases], use [CaseOf] to create it.
+type GenericCase struct {
+	typ		reflect.Type
+	verify	func() error
+}
+
+// CaseOf returns the [GenericCase] to verify the clone function of the
+// structure type T, that is usually an instantiation of a generic type. The
+// verification is performed by [VerifyGeneric] with the options opts, the
+// verified structures are created by new(T).
+func CaseOf[T any](clone func(*T) *T, opts ...Option) GenericCase {
+	return GenericCase{
+		typ:	reflect.TypeOf((*T)(nil)),
+		verify:	func() error {
+			return VerifyGeneric(func() *T { return new(T) }, clone, opts...)
+		},
+	}
+}
+
+/*
+VerifyGenericCases verifies several instantiations of generic types, each
+instantiation is verified by [VerifyGeneric]. The types of fields resolved from
+the type parameters, including containers like map[K]V or []K, are concrete
+types of the instantiation, so they are handled by the Setter and Changer
+functions of these concrete types:
+
+  type Pair[K comparable, V any] struct {
+      Key   K
+      Value V
+      Index map[K]V
+  }
+
+  err := clone.VerifyGenericCases(
+      clone.CaseOf((*Pair[string, int]).Clone),
+      clone.CaseOf((*Pair[int, []string]).Clone),
+  )
+
+The verification of the remaining cases continues after a failure. If any
+verification fails, an *[ErrSVAggregate] containing the errors of all failed
+cases is returned.
+*/
+func VerifyGenericCases(cases ...GenericCase) error {
+	var errs []error
+
+	for i, c := range cases {
+		if err := c.verify(); err != nil {
+			errs = append(errs, fmt.Errorf("case #%d (%v): %w", i, c.typ, err))
+		}
+	}
+
+	if errs != nil {
+		return &ErrSVAggregate{Errs: errs}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+NewStructVerifierFrom works like [NewStructVerifierWith], but derives the
+creator and the cloner functions from the sample value. It is useful when the
+Clone method is exposed through an interface I and returns an interface R
+instead of the concrete type:
+
+  type Shape interface {
+      Clone() Shape
+  }
+
+  sv := clone.NewStructVerifierFrom(&Circle{}, Shape.Clone)
+
+The sample must be a non-nil pointer to a structure implementing I, otherwise
+NewStructVerifierFrom panics. The creator returns a new zero value of the
+concrete type of the sample, created by [reflect.New], so the fields of the
+concrete type are verified. The cloner calls the clone function, the value
+it returns must have the same concrete type as the sample.
+*/
+func NewStructVerifierFrom[I, R any](sample any, clone func(I) R, opts ...Option) *StructVerifier {
+	st := reflect.TypeOf(sample)
+	if st == nil || st.Kind() != reflect.Pointer || st.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("NewStructVerifierFrom: sample must be a pointer to a structure, got - %T", sample))
+	}
+	if _, ok := sample.(I); !ok {
+		panic(fmt.Sprintf("NewStructVerifierFrom: sample of type %T does not implement %v",
+			sample, reflect.TypeOf((*I)(nil)).Elem()))
+	}
+
+	return NewStructVerifierWith(
+		func() any { return reflect.New(st.Elem()).Interface() },
+		func(x any) any {
+			v, ok := x.(I)
+			if !ok {
+				panic(fmt.Sprintf("unsupported type to clone: got - %T, want - %v", x, reflect.TypeOf((*I)(nil)).Elem()))
+			}
+			return clone(v)
+		},
+		opts...,
+	)
+}
+
+/*
+NewStructVerifierMulti works like [NewStructVerifierWith], but accepts the
+cloner function returning multiple values, such as Clone methods returning
+some metadata along with the clone:
+
+  func (t *Tree) CloneDepth() (*Tree, int)
+
+  sv := clone.NewStructVerifierMulti(func() any { return &Tree{} }, (*Tree).CloneDepth, 0)
+
+The cloner must be a function with a single parameter, the value returned by the
+creator is passed to it. The result with the index n (counting from 0) is used
+as the clone for verification, other results are ignored. NewStructVerifierMulti
+panics if cloner is not a function of such signature or n is out of range of
+its results.
+*/
+func NewStructVerifierMulti(creator CreatorFunc, cloner any, n int, opts ...Option) *StructVerifier {
+	cv := reflect.ValueOf(cloner)
+	if cv.Kind() != reflect.Func || cv.IsNil() || cv.Type().NumIn() != 1 || cv.Type().IsVariadic() {
+		panic(fmt.Sprintf("NewStructVerifierMulti: cloner must be a function with a single parameter, got - %T", cloner))
+	}
+	if n < 0 || n >= cv.Type().NumOut() {
+		panic(fmt.Sprintf("NewStructVerifierMulti: result index %d is out of range of results of %T", n, cloner))
+	}
+
+	in := cv.Type().In(0)
+
+	return NewStructVerifierWith(
+		creator,
+		func(x any) any {
+			xv := reflect.ValueOf(x)
+			if !xv.IsValid() || !xv.Type().AssignableTo(in) {
+				panic(fmt.Sprintf("unsupported type to clone: got - %T, want - %v", x, in))
+			}
+			return cv.Call([]reflect.Value{xv})[n].Interface()
+		},
+		opts...,
+	)
+}
+
+/*
+VerifyConcurrent verifies the clone independence by concurrent access, it is
+designed to be run with the race detector enabled:
+
+  go test -race ./...
+
+It creates the filled original object and its clone, then launches goroutines
+that change the fields of the clone, while other goroutines read the fields of
+the original. If the clone shares any storage with the original, the race
+detector reports the data race and the test fails. Without the race detector,
+only the comparison of the original with the reference is performed after all
+goroutines finish, that returns the *[ErrSVOrigChanged] error if the original
+has been changed.
+
+The options are applied to the [StructVerifier] used to fill and change fields.
+The same fields as by [StructVerifier.Verify] are changed: fields expected to be
+shared, fields of function types and arrays of zero length are left intact.
+*/
+func VerifyConcurrent(creator CreatorFunc, cloner ClonerFunc, opts ...Option) error {
+	sv := NewStructVerifierWith(creator, cloner, opts...)
+
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		return err
+	}
+
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	// Fields expected to be shared, functions and empty arrays are not changed, as by Verify
+	fields := sv.changeableFields(sv.verifiedFields())
+	errs := make([]error, len(fields))
+
+	var wg sync.WaitGroup
+	for i, field := range fields {
+		wg.Add(2)
+
+		// Change the field of the clone
+		go func(i int, field string) {
+			defer wg.Done()
+			for n := 0; n < concurrentIters; n++ {
+				if err := sv.autoChange(clone, field); err != nil {
+					errs[i] = &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field, err)}
+					return
+				}
+			}
+		}(i, field)
+
+		// Read the field of the original
+		go func(field string) {
+			defer wg.Done()
+			for n := 0; n < concurrentIters; n++ {
+				deepCopy(fieldByPath(reflect.ValueOf(orig).Elem(), field), map[uintptr]reflect.Value{})
+			}
+		}(field)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// Compare the original and the reference - they should be the same
+	if !sv.equal(orig, ref) {
+		return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+			" after CONCURRENT CHANGES of the CLONE: %s%s", sv.dump(orig), sv.dump(ref), sv.dump(clone),
+			sv.diffNote(ref, orig, "ref", "orig"))}
+	}
+
+	// OK
+	return nil
+}
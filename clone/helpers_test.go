@@ -0,0 +1,368 @@
+package clone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// shallowCloner returns a simple copy of the structure without copying complex fields
+func shallowCloner(x any) any {
+	switch v := x.(type) {
+	case *struct{I int}:
+		rv := *v
+		return &rv
+	case *struct{S []string}:
+		rv := *v
+		return &rv
+	default:
+		return x
+	}
+}
+
+func TestVerifyWithCloner(t *testing.T) {
+	if err := VerifyWithCloner(shallowCloner,
+		func() any { return &struct{I int}{} },
+	); err != nil {
+		t.Errorf("verification of the simple type failed: %v", err)
+	}
+}
+
+func TestVerifyWithClonerAggregate(t *testing.T) {
+	err := VerifyWithCloner(shallowCloner,
+		func() any { return &struct{I int}{} },
+		func() any { return &struct{S []string}{} },	// shared slice
+		func() any { return &struct{B bool}{} },		// unsupported type
+	)
+
+	var errAggr *ErrSVAggregate
+	if !errors.As(err, &errAggr) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVAggregate", err, err)
+	}
+
+	if len(errAggr.Errs) != 2 {
+		t.Errorf("got %d aggregated errors, want - 2: %v", len(errAggr.Errs), err)
+	}
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("aggregated error does not contain *ErrSVOrigChanged: %v", err)
+	}
+	if !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("aggregated error does not contain *ErrSVOrigFill: %v", err)
+	}
+
+	// Methods used by Go versions without unwrapping of multiple errors
+	if !errAggr.As(new(*ErrSVOrigChanged)) || errAggr.As(new(*ErrSVClonePanic)) {
+		t.Errorf("As method of aggregated error returned unexpected result: %v", err)
+	}
+	if !errAggr.Is(errAggr.Errs[0]) || errAggr.Is(errors.New("other")) {
+		t.Errorf("Is method of aggregated error returned unexpected result: %v", err)
+	}
+}
+
+type testContainer[T any] struct {
+	Items	[]T
+	Count	int
+	Last	*int
+}
+
+func (c *testContainer[T]) Clone() *testContainer[T] {
+	rv := *c
+
+	rv.Items = make([]T, len(c.Items))
+	copy(rv.Items, c.Items)
+
+	if c.Last != nil {
+		last := *c.Last
+		rv.Last = &last
+	}
+
+	return &rv
+}
+
+// ShallowClone returns a clone that shares items with the original
+func (c *testContainer[T]) ShallowClone() *testContainer[T] {
+	rv := c.Clone()
+	rv.Items = c.Items
+	return rv
+}
+
+func TestVerifyGeneric(t *testing.T) {
+	if err := VerifyGeneric(func() *testContainer[int] { return &testContainer[int]{} },
+		(*testContainer[int]).Clone); err != nil {
+		t.Errorf("verification of testContainer[int] failed: %v", err)
+	}
+
+	if err := VerifyGeneric(func() *testContainer[string] { return &testContainer[string]{} },
+		(*testContainer[string]).Clone); err != nil {
+		t.Errorf("verification of testContainer[string] failed: %v", err)
+	}
+
+	err := VerifyGeneric(func() *testContainer[string] { return &testContainer[string]{} },
+		(*testContainer[string]).ShallowClone)
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testPair[K comparable, V any] struct {
+	Key		K
+	Value	V
+	Index	map[K]V
+	Keys	[]K
+}
+
+// cloneValue returns a deep copy of v, shallow copies of values are returned
+// if shallow is true
+func cloneValue[V any](v V, shallow bool) V {
+	if s, ok := any(v).([]string); ok && !shallow && s != nil {
+		return any(append([]string(nil), s...)).(V)	//nolint:forcetypeassert
+	}
+
+	return v
+}
+
+func (p *testPair[K, V]) clone(shallow bool) *testPair[K, V] {
+	rv := &testPair[K, V]{Key: p.Key, Value: cloneValue(p.Value, shallow)}
+
+	if p.Index != nil {
+		rv.Index = make(map[K]V, len(p.Index))
+		for k, v := range p.Index {
+			rv.Index[k] = cloneValue(v, shallow)
+		}
+	}
+	rv.Keys = append([]K(nil), p.Keys...)
+
+	return rv
+}
+
+func (p *testPair[K, V]) Clone() *testPair[K, V] {
+	return p.clone(false)
+}
+
+// ShallowClone returns a clone that shares values with the original
+func (p *testPair[K, V]) ShallowClone() *testPair[K, V] {
+	return p.clone(true)
+}
+
+func TestVerifyGenericCases(t *testing.T) {
+	if err := VerifyGenericCases(
+		CaseOf((*testPair[string, int]).Clone),
+		CaseOf((*testPair[int, []string]).Clone),
+	); err != nil {
+		t.Errorf("verification of testPair instantiations failed: %v", err)
+	}
+
+	err := VerifyGenericCases(
+		CaseOf((*testPair[string, int]).ShallowClone),	// OK, no shared values
+		CaseOf((*testPair[int, []string]).ShallowClone),
+	)
+
+	var errAggr *ErrSVAggregate
+	if !errors.As(err, &errAggr) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVAggregate", err, err)
+	}
+	if len(errAggr.Errs) != 1 || !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("want only *ErrSVOrigChanged of testPair[int, []string], got: %v", err)
+	}
+}
+
+func TestVerifyConcurrent(t *testing.T) {
+	// The clone shares no storage with the original, so there must be
+	// no data races reported if the test is run with the race detector
+	if err := VerifyConcurrent(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+		WithSetters(intSliceSetter),
+		WithChangers(intSliceChanger),
+	); err != nil {
+		t.Errorf("concurrent verification failed: %v", err)
+	}
+}
+
+func TestVerifyConcurrentUnchangeable(t *testing.T) {
+	type testHandler struct {
+		Name	string
+		Tags	[]string
+		Handle	func()
+		Cache	map[string]int	`clone:"shared"`
+		Routes	map[string]int
+		Pad		[0]int
+	}
+
+	// Functions, shared fields and empty arrays must not be changed
+	if err := VerifyConcurrent(
+		func() any { return &testHandler{} },
+		func(x any) any {
+			rv := *x.(*testHandler)	//nolint:forcetypeassert
+			rv.Tags = append([]string(nil), rv.Tags...)
+			return &rv
+		},
+		WithExpectShared("Routes"),
+	); err != nil {
+		t.Errorf("concurrent verification of structure with function and shared fields failed: %v", err)
+	}
+}
+
+type testShape interface {
+	Clone() testShape
+}
+
+type testCircle struct {
+	Center	[]int
+	Radius	int
+}
+
+func (c *testCircle) Clone() testShape {
+	rv := *c
+	rv.Center = append([]int(nil), c.Center...)
+	return &rv
+}
+
+type testSquare struct {
+	Corners	[]int
+}
+
+// Clone returns a clone that shares corners with the original
+func (s *testSquare) Clone() testShape {
+	rv := *s
+	return &rv
+}
+
+func TestNewStructVerifierFrom(t *testing.T) {
+	if err := NewStructVerifierFrom(&testCircle{}, testShape.Clone).Verify(); err != nil {
+		t.Errorf("verification of the interface clone method failed: %v", err)
+	}
+
+	err := NewStructVerifierFrom(&testSquare{}, testShape.Clone).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	// The sample must implement the interface
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewStructVerifierFrom did not panic on the sample not implementing the interface")
+		}
+	}()
+	NewStructVerifierFrom(&struct{I int}{}, testShape.Clone)
+}
+
+type testTree struct {
+	Nodes	[]int
+	Name	string
+}
+
+// CloneDepth returns a clone and the depth of the cloned tree
+func (t *testTree) CloneDepth() (*testTree, int) {
+	return &testTree{Nodes: append([]int(nil), t.Nodes...), Name: t.Name}, 1
+}
+
+// ShallowDepth returns the depth of the tree and the clone sharing nodes
+func (t *testTree) ShallowDepth() (int, *testTree) {
+	rv := *t
+	return 1, &rv
+}
+
+func TestNewStructVerifierMulti(t *testing.T) {
+	creator := func() any { return &testTree{} }
+
+	if err := NewStructVerifierMulti(creator, (*testTree).CloneDepth, 0).Verify(); err != nil {
+		t.Errorf("verification of the clone method with multiple results failed: %v", err)
+	}
+
+	err := NewStructVerifierMulti(creator, (*testTree).ShallowDepth, 1).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	// The result index must be in range of results
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewStructVerifierMulti did not panic on the result index out of range")
+		}
+	}()
+	NewStructVerifierMulti(creator, (*testTree).CloneDepth, 2)
+}
+
+func TestCompareCloners(t *testing.T) {
+	type testConf struct {
+		Name	string
+		Vals	[]int
+	}
+	creator := func() any { return &testConf{} }
+	oldClone := func(x any) any {
+		orig := x.(*testConf)	//nolint:forcetypeassert
+		return &testConf{Name: orig.Name, Vals: append([]int(nil), orig.Vals...)}
+	}
+	newClone := func(x any) any {
+		rv := *x.(*testConf)	//nolint:forcetypeassert
+		rv.Vals = make([]int, len(rv.Vals))
+		copy(rv.Vals, x.(*testConf).Vals)	//nolint:forcetypeassert
+		return &rv
+	}
+
+	if err := CompareCloners(creator, oldClone, newClone); err != nil {
+		t.Errorf("comparison of equivalent cloners failed: %v", err)
+	}
+
+	// The second cloner shares values with the original
+	err := CompareCloners(creator, oldClone, func(x any) any {
+		rv := *x.(*testConf)	//nolint:forcetypeassert
+		return &rv
+	})
+	if !errors.As(err, new(*ErrSVOrigChanged)) || !strings.HasPrefix(err.Error(), "second cloner: ") {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged of the second cloner", err, err)
+	}
+
+	// The second cloner produces different clones, that are
+	// correct according to the user-defined comparator
+	var errDiverge *ErrSVClonersDiverge
+	err = CompareCloners(creator, oldClone, func(x any) any {
+		rv := oldClone(x).(*testConf)	//nolint:forcetypeassert
+		rv.Name = strings.ToUpper(rv.Name)
+		return rv
+	}, WithComparator(strings.EqualFold))
+	switch {
+	case errors.As(err, &errDiverge):
+		if errDiverge.Path != "Name" {
+			t.Errorf("got divergence path %q, want - %q", errDiverge.Path, "Name")
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVClonersDiverge", err, err)
+	}
+}
+
+type testRecord struct {
+	ID		int
+	Fields	[]string
+}
+
+// Clone returns a deep copy of the record
+func (r testRecord) Clone() testRecord {
+	r.Fields = append([]string(nil), r.Fields...)
+	return r
+}
+
+// ClonePtr returns a copy of the record sharing fields with the original
+func (r *testRecord) ClonePtr() *testRecord {
+	rv := *r
+	return &rv
+}
+
+func TestVerifyReceiverCloners(t *testing.T) {
+	creator := func() any { return &testRecord{} }
+	valueCloner := func(x any) any { c := x.(*testRecord).Clone(); return &c }	//nolint:forcetypeassert
+
+	if err := VerifyReceiverCloners(creator, valueCloner, valueCloner); err != nil {
+		t.Errorf("verification of correct cloners failed: %v", err)
+	}
+
+	err := VerifyReceiverCloners(creator, valueCloner,
+		func(x any) any { return x.(*testRecord).ClonePtr() })	//nolint:forcetypeassert
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+	if err != nil && !strings.HasPrefix(err.Error(), "pointer receiver cloner: ") {
+		t.Errorf("error %q does not name the failed pointer receiver cloner", err)
+	}
+}
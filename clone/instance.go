@@ -0,0 +1,185 @@
+package clone
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ErrSVInstanceTypeMismatch represents an error that occurs when the original
+// passed to [StructVerifier.VerifyInstance] has the type other than the type of
+// structures created by the creator function. Type contains the type of the
+// original, Want contains the type returned by the creator.
+type ErrSVInstanceTypeMismatch struct {
+	structVerifierError
+	Type	reflect.Type
+	Want	reflect.Type
+}
+
+/*
+VerifyInstance verifies the cloner function using the pre-filled original
+object orig instead of the automatically filled one. It is useful when the
+field values must satisfy invariants that cannot be produced by the Setter
+functions. The orig must be a non-nil pointer to a structure of the same type
+as created by the creator function, otherwise *[ErrSVOrigFill] or
+*[ErrSVInstanceTypeMismatch] is returned.
+
+The automatic filling and the comparison of the original object with the
+reference one are skipped. The verification is performed on a deep copy of the
+exported fields of orig, the reference object is another such copy. Then each
+field is verified the same way as by [StructVerifier.Verify], so the Changer
+functions must support the values of orig. The embedded Changer functions add
+elements to nil and empty slices and maps. The exported fields of orig are not
+modified by the verification even if the cloner shares them with the clone,
+but the values of unexported fields are copied as is, so a cloner sharing them
+may modify orig through them.
+
+The additional phase enabled by [StructVerifier.ChangeAllFields] is performed
+too, but the fields are always verified one by one regardless of the
+parallelism. The other phases of Verify are skipped: the check of different
+values produced by setters for different fields, skipping of unchangeable
+fields in the partial mode, the checks of accessors, registered methods,
+optional, external and nested types, and the phases enabled by
+[StructVerifier.CheckNilInterfaces], [StructVerifier.CheckNilReceiver],
+[StructVerifier.InsertMapKeys], [StructVerifier.CheckNilPointers],
+[StructVerifier.CheckCrossFieldAliasing], [StructVerifier.CheckSparseFields],
+[StructVerifier.CheckEmptyCapacity] and [StructVerifier.CheckDeterministic].
+*/
+func (sv *StructVerifier) VerifyInstance(orig any) error {
+	// Reset warnings of the previous verification
+	sv.warnings = nil
+
+	if v := reflect.ValueOf(orig); v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return &ErrSVOrigFill{newErrSV("original must be a non-nil pointer to a structure, got - %T", orig)}
+	}
+
+	if ot, want := reflect.TypeOf(orig), reflect.TypeOf(sv.creator()); ot != want {
+		return &ErrSVInstanceTypeMismatch{
+			structVerifierError:	newErrSV("original has type %v, but the creator returns %v", ot, want),
+			Type:					ot,
+			Want:					want,
+		}
+	}
+
+	// Verify a copy to keep the instance unchanged if the cloner shares its data
+	orig = deepCopy(reflect.ValueOf(orig), map[uintptr]reflect.Value{}).Interface()
+	// Make the reference to compare after clone modifications
+	ref := deepCopy(reflect.ValueOf(orig), map[uintptr]reflect.Value{}).Interface()
+
+	fields := sv.verifiedFields()
+	for _, field := range fields {
+		if err := sv.verifyField(orig, ref, field); err != nil {
+			return err
+		}
+	}
+
+	// Check all fields changed at once if required
+	if sv.allFields {
+		if err := sv.verifyAllFields(orig, ref, fields); err != nil {
+			return err
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// deepCopy returns a deep copy of v. Unexported fields of structures are
+// copied as is, because they cannot be set. The copies of already visited
+// pointers are reused to preserve cycles of the original
+func deepCopy(v reflect.Value, visited map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() { //nolint:exhaustive	// other kinds are copied by value
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := visited[v.Pointer()]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		visited[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), visited))
+
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), visited))
+
+		return c
+
+	case reflect.Struct:
+		// Copy the value stored in the atomic wrapper
+		if av, ok := atomicLoad(v); ok {
+			c := reflect.New(v.Type())
+			if av.Kind() != reflect.Interface || !av.IsNil() {
+				c.MethodByName("Store").Call([]reflect.Value{deepCopy(av, visited)})
+			}
+
+			return c.Elem()
+		}
+
+		// Copy the entries of the sync.Map value
+		if m, ok := syncMapOf(v); ok {
+			c := &sync.Map{}
+			m.Range(func(key, val any) bool {
+				if val != nil {
+					val = deepCopy(reflect.ValueOf(val), visited).Interface()
+				}
+				c.Store(key, val)
+				return true
+			})
+
+			return reflect.ValueOf(c).Elem()
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if isExported(v.Type().Field(i).Name) {
+				c.Field(i).Set(deepCopy(v.Field(i), visited))
+			}
+		}
+
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value(), visited))
+		}
+
+		return c
+	}
+
+	return v
+}
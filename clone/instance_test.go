@@ -0,0 +1,120 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVerifyInstance(t *testing.T) {
+	// Values satisfy the invariant: IntList contains only even numbers
+	orig := &testComplexStruct{
+		Int64param:	42,
+		IntList:	[]int{2, 4, 6},
+		Int64List:	[]int64{10, 20},
+		StringList:	[]string{"first", "second"},
+		MapVals:	map[string]any{"one": 1, "two": 2},
+	}
+
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+	).AddChangers(intSliceChanger)
+
+	if err := sv.VerifyInstance(orig); err != nil {
+		t.Errorf("verification of the pre-filled instance failed: %v", err)
+	}
+
+	// The original must not be modified
+	if orig.IntList[0] != 2 || orig.Int64param != 42 {
+		t.Errorf("the original instance was modified by the verification: %#v", orig)
+	}
+
+	// Shallow copy, slices and maps are shared
+	err := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { rv := *x.(*testComplexStruct); return &rv },	//nolint:forcetypeassert
+	).AddChangers(intSliceChanger).VerifyInstance(orig)
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares slices and maps")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error, the shared data must not be changed through the clone
+		if orig.IntList[2] != 6 || orig.StringList[1] != "second" || orig.MapVals["two"] != 2 {
+			t.Errorf("the original instance was modified through the shared data: %#v", orig)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	if err := sv.VerifyInstance(*orig); !errors.As(err, new(*ErrSVOrigFill)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
+
+func TestVerifyInstanceTypeMismatch(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+	).AddChangers(intSliceChanger)
+
+	err := sv.VerifyInstance(&testEmptyFields{})
+	var tErr *ErrSVInstanceTypeMismatch
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the instance has another type")
+	case errors.As(err, &tErr):
+		if tErr.Type != reflect.TypeOf(&testEmptyFields{}) || tErr.Want != reflect.TypeOf(&testComplexStruct{}) {
+			t.Errorf("got types %v and %v in the error: %v", tErr.Type, tErr.Want, err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVInstanceTypeMismatch", err, err)
+	}
+}
+
+type testEmptyFields struct {
+	Ints		[]int
+	Int64s		[]int64
+	Strings		[]string
+	Durations	[]time.Duration
+	Attrs		map[string]any
+}
+
+// cloneEmptyFields returns a deep copy of testEmptyFields, nil and empty
+// slices and maps are kept as is
+func cloneEmptyFields(x any) any {
+	orig := x.(*testEmptyFields)	//nolint:forcetypeassert
+	rv := &testEmptyFields{
+		Ints:		append(orig.Ints[:0:0], orig.Ints...),
+		Int64s:		append(orig.Int64s[:0:0], orig.Int64s...),
+		Strings:	append(orig.Strings[:0:0], orig.Strings...),
+		Durations:	append(orig.Durations[:0:0], orig.Durations...),
+	}
+	if orig.Attrs != nil {
+		rv.Attrs = cloneAnyMap(orig.Attrs, true)
+	}
+
+	return rv
+}
+
+func TestVerifyInstanceEmpty(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testEmptyFields{} }, cloneEmptyFields)
+
+	// Changers must grow nil slices and maps
+	if err := sv.VerifyInstance(&testEmptyFields{}); err != nil {
+		t.Errorf("verification of the instance with nil slices and maps failed: %v", err)
+	}
+
+	// Changers must grow empty slices and maps
+	empty := &testEmptyFields{
+		Ints:		[]int{},
+		Int64s:		[]int64{},
+		Strings:	[]string{},
+		Durations:	[]time.Duration{},
+		Attrs:		map[string]any{},
+	}
+	if err := sv.VerifyInstance(empty); err != nil {
+		t.Errorf("verification of the instance with empty slices and maps failed: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package clone
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ErrSVRoundTripDiverged represents an error that occurs when fields of the
+// original differ from the same fields of the clone made by the serialization
+// round-trip, see [VerifyJSONRoundTrip] and [VerifyBinaryRoundTrip]. Fields
+// contains the names of the diverged fields.
+type ErrSVRoundTripDiverged struct {
+	structVerifierError
+	Fields	[]string
+}
+
+/*
+JSONCloner returns the cloner function that clones the structure by marshaling
+it to JSON and unmarshaling the result into a new instance created by the
+creator function. The cloner panics if the structure cannot be marshaled or
+unmarshaled, e.g. it has fields of channel or function types, the panic is
+reported by the verification as *[ErrSVClonePanic].
+*/
+func JSONCloner(creator CreatorFunc) ClonerFunc {
+	return func(x any) any {
+		data, err := json.Marshal(x)
+		if err != nil {
+			panic(fmt.Sprintf("cannot marshal %T to JSON: %v", x, err))
+		}
+
+		rv := creator()
+		if err := json.Unmarshal(data, rv); err != nil {
+			panic(fmt.Sprintf("cannot unmarshal JSON to %T: %v", rv, err))
+		}
+
+		return rv
+	}
+}
+
+/*
+VerifyJSONRoundTrip verifies cloning of the structure by the JSON round-trip,
+see [JSONCloner]. Such clones are independent of the original by construction,
+but may silently lose data: unexported fields are dropped, empty slices and
+maps of fields tagged by omitempty become nil, values of interface fields
+become maps and float64 numbers, and so on.
+
+Before the regular verification, the filled original is cloned once and its
+exported fields are compared with the fields of the clone, the fields that
+differ are reported by the *[ErrSVRoundTripDiverged] error. If nilEmptyEqual
+is set, nil and empty slices and maps are treated equal both in this check and
+in the verification, see [StructVerifier.TreatNilAndEmptyEqual]. The verifier
+is configured by the options, for example:
+
+  err := clone.VerifyJSONRoundTrip(func() any { return &Config{} }, true)
+*/
+func VerifyJSONRoundTrip(creator CreatorFunc, nilEmptyEqual bool, opts ...Option) error {
+	sv := NewStructVerifierWith(creator, JSONCloner(creator), opts...)
+	if nilEmptyEqual {
+		sv.TreatNilAndEmptyEqual()
+	}
+
+	return sv.verifyRoundTrip("JSON")
+}
+
+// verifyRoundTrip checks that the exported fields of the filled original are
+// not changed by the round-trip through the format, then runs the verification
+func (sv *StructVerifier) verifyRoundTrip(format string) error {
+	orig, err := sv.SampleFilled()
+	if err != nil {
+		return err
+	}
+
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	var diverged []string
+	ov, cv := reflect.ValueOf(orig).Elem(), reflect.ValueOf(clone).Elem()
+	for _, field := range sv.verifiedFields() {
+		if !sv.equalField(fieldByPath(ov, field), fieldByPath(cv, field), field) {
+			diverged = append(diverged, field)
+		}
+	}
+	if diverged != nil {
+		return &ErrSVRoundTripDiverged{
+			structVerifierError:	newErrSV("fields %q of the ORIGINAL DIVERGE after %s round-trip:" +
+										" orig - %s, clone - %s", diverged, format, sv.dump(orig), sv.dump(clone)),
+			Fields:					diverged,
+		}
+	}
+
+	return sv.Verify()
+}
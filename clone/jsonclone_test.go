@@ -0,0 +1,78 @@
+package clone
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testJSONConfig struct {
+	Name	string
+	Tags	[]string
+	Limits	map[string]int
+	Backup	*testJSONConfig	`json:",omitempty"`
+}
+
+type testJSONLossy struct {
+	Name	string
+	Hosts	[]string	`json:"-"`
+	Ports	[]int
+}
+
+func TestVerifyJSONRoundTrip(t *testing.T) {
+	if err := VerifyJSONRoundTrip(func() any { return &testJSONConfig{} }, false); err != nil {
+		t.Errorf("verification of JSON round-trip failed: %v", err)
+	}
+
+	err := VerifyJSONRoundTrip(func() any { return &testJSONLossy{} }, false)
+	var rErr *ErrSVRoundTripDiverged
+	if !errors.As(err, &rErr) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVRoundTripDiverged", err, err)
+	}
+	if want := []string{"Hosts"}; !reflect.DeepEqual(rErr.Fields, want) {
+		t.Errorf("got diverged fields %q, want - %q", rErr.Fields, want)
+	}
+
+	// Comparison settings must be applied to the round-trip check too
+	if err := VerifyJSONRoundTrip(func() any { return &testJSONConfig{} }, true); err != nil {
+		t.Errorf("verification of JSON round-trip treating nil and empty equal failed: %v", err)
+	}
+}
+
+// testJSONCode is marshaled in upper case, the case is not significant
+type testJSONCode string
+
+func (c testJSONCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(string(c)))
+}
+
+type testJSONRegion struct {
+	Name	string
+	Code	testJSONCode
+}
+
+func TestVerifyJSONRoundTripFieldComparator(t *testing.T) {
+	creator := func() any { return &testJSONRegion{} }
+
+	err := VerifyJSONRoundTrip(creator, false)
+	var rErr *ErrSVRoundTripDiverged
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the case of codes is changed by the round-trip")
+	case errors.As(err, &rErr):
+		if want := []string{"Code"}; !reflect.DeepEqual(rErr.Fields, want) {
+			t.Errorf("got diverged fields %q, want - %q", rErr.Fields, want)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVRoundTripDiverged", err, err)
+	}
+
+	// The field comparator must be applied by the round-trip check
+	if err := VerifyJSONRoundTrip(creator, false, WithFieldComparator("Code", func(a, b any) bool {
+		return strings.EqualFold(string(a.(testJSONCode)), string(b.(testJSONCode)))	//nolint:forcetypeassert
+	})); err != nil {
+		t.Errorf("verification of JSON round-trip with field comparator failed: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrSVMethodDiverged represents an error that occurs when the results of an
+// exported method registered by [StructVerifier.AddMethodCheck] differ. Method
+// contains the name of the method. Field is empty if the results for the newly
+// created clone differ from the results for the original, otherwise it contains
+// the name of the clone field, which change has changed the results for the
+// original.
+type ErrSVMethodDiverged struct {
+	structVerifierError
+	Method	string
+	Field	string
+}
+
+// methodCheck describes the method called on the original and the clone
+type methodCheck struct {
+	name	string
+	args	[]reflect.Value
+}
+
+/*
+AddMethodCheck registers the exported method to verify the behavioral
+independence of the clone: some types keep the state reachable only through
+their methods, e.g. caches or buffers in unexported fields, so the equality of
+the exported fields does not prove that the clone behaves as the original. The
+method with the given name is called on the pointer to the structure with the
+args, it must not modify the structure.
+
+The registered methods are checked in an additional phase. For each verified
+field, the method is called on the newly created clone and on the original,
+the results must be equal. Then the field of the clone is changed and the
+method is called on the original again, the results must remain the same.
+Otherwise, the *[ErrSVMethodDiverged] error is returned.
+
+AddMethodCheck panics if the structure has no such method or args do not
+match its parameters. For example:
+
+  sv.AddMethodCheck("Lookup", "key")
+*/
+func (sv *StructVerifier) AddMethodCheck(name string, args ...any) *StructVerifier {
+	t := reflect.TypeOf(sv.creator())
+	m, ok := t.MethodByName(name)
+	if !ok {
+		panic(fmt.Sprintf("AddMethodCheck: type %v has no exported method %q", t, name))
+	}
+
+	// The receiver is the first parameter of the method
+	mt := m.Type
+	if !mt.IsVariadic() && mt.NumIn() - 1 != len(args) || mt.IsVariadic() && mt.NumIn() - 2 > len(args) {
+		panic(fmt.Sprintf("AddMethodCheck: method %q of type %v takes %d arguments, got - %d",
+			name, t, mt.NumIn() - 1, len(args)))
+	}
+
+	check := methodCheck{name: name, args: make([]reflect.Value, 0, len(args))}
+	for i, arg := range args {
+		// Type of the parameter, the rest arguments of variadic methods are elements of the last one
+		pt := mt.In(mt.NumIn() - 1)
+		if !mt.IsVariadic() || i + 1 < mt.NumIn() - 1 {
+			pt = mt.In(i + 1)
+		} else {
+			pt = pt.Elem()
+		}
+
+		av := reflect.ValueOf(arg)
+		if !av.IsValid() {
+			// Untyped nil argument
+			av = reflect.Zero(pt)
+		}
+		if !av.Type().AssignableTo(pt) {
+			panic(fmt.Sprintf("AddMethodCheck: argument #%d of type %v cannot be used as %v in method %q",
+				i, av.Type(), pt, name))
+		}
+		check.args = append(check.args, av)
+	}
+
+	sv.methodChecks = append(sv.methodChecks, check)
+
+	return sv
+}
+
+// call calls the method on x and returns its results
+func (mc methodCheck) call(x any) []any {
+	out := reflect.ValueOf(x).MethodByName(mc.name).Call(mc.args)
+
+	rv := make([]any, 0, len(out))
+	for _, v := range out {
+		rv = append(rv, v.Interface())
+	}
+
+	return rv
+}
+
+// describe returns the method call with arguments as a string
+func (mc methodCheck) describe() string {
+	args := make([]string, 0, len(mc.args))
+	for _, arg := range mc.args {
+		args = append(args, fmt.Sprintf("%#v", arg.Interface()))
+	}
+
+	return mc.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// verifyMethods calls the registered methods on the original and clones with
+// changed fields from the fields list and checks that the results are the same
+func (sv *StructVerifier) verifyMethods(orig any, fields []string) error {
+	for _, mc := range sv.methodChecks {
+		for _, field := range fields {
+			clone, err := sv.callCloner(orig, field)
+			if err != nil {
+				return err
+			}
+
+			want := mc.call(orig)
+
+			if got := mc.call(clone); !sv.equal(want, got) {
+				return &ErrSVMethodDiverged{
+					structVerifierError:	newErrSV("method %s returned %s for the newly created CLONE," +
+												" but %s for the ORIGINAL", mc.describe(), sv.dump(got), sv.dump(want)),
+					Method:					mc.name,
+				}
+			}
+
+			if err := sv.autoChange(clone, field); err != nil {
+				return &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field,  err)}
+			}
+
+			if got := mc.call(orig); !sv.equal(want, got) {
+				return &ErrSVMethodDiverged{
+					structVerifierError:	newErrSV("method %s of the ORIGINAL returned %s instead of %s" +
+												" after the CLONE FIELD ----> %q <---- has been CHANGED",
+												mc.describe(), sv.dump(got), sv.dump(want), field),
+					Method:					mc.name,
+					Field:					field,
+				}
+			}
+		}
+	}
+
+	// OK
+	return nil
+}
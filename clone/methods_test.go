@@ -0,0 +1,128 @@
+package clone
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type testLedger struct {
+	Amounts	[]int
+	Owner	string
+	pool	func(l *testLedger) []int	// hidden buffer, functions are compared by code only
+}
+
+// ledgerPools holds the hidden buffers of ledgers, all ledgers take them by the
+// same function ledgerPool, so the pool fields of ledgers are always equal
+var ledgerPools sync.Map
+
+// ledgerPool returns the hidden buffer of l
+func ledgerPool(l *testLedger) []int {
+	buf, _ := ledgerPools.Load(l)
+	rv, _ := buf.([]int)
+	return rv
+}
+
+// newLedger returns a ledger with the hidden buffer of size n
+func newLedger(n int) *testLedger {
+	l := &testLedger{pool: ledgerPool}
+	ledgerPools.Store(l, make([]int, n))
+	return l
+}
+
+func (l *testLedger) Pooled() []int {
+	return append([]int(nil), l.pool(l)...)
+}
+
+// cloneLedger returns a clone of testLedger, the pool of the clone has no
+// buffer if emptyPool is set, the amounts of the clone are stored in the pool
+// of the original if borrowPool is set
+func cloneLedger(x any, emptyPool, borrowPool bool) any {
+	orig := x.(*testLedger)	//nolint:forcetypeassert
+	buf := orig.pool(orig)
+
+	size := len(buf)
+	if emptyPool {
+		size = 0
+	}
+	rv := newLedger(size)
+	rv.Owner = orig.Owner
+	copy(rv.pool(rv), buf)
+
+	if borrowPool && len(buf) >= len(orig.Amounts) {
+		rv.Amounts = buf[:len(orig.Amounts)]
+		copy(rv.Amounts, orig.Amounts)
+	} else {
+		rv.Amounts = append([]int(nil), orig.Amounts...)
+	}
+
+	return rv
+}
+
+func TestAddMethodCheck(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return newLedger(genericLen) },
+		func(x any) any { return cloneLedger(x, false, false) },
+		WithMethodCheck("Pooled"),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the clone with hidden state failed: %v", err)
+	}
+
+	// Invalid methods must be reported on registration
+	for _, args := range [][]any{ {"Missing"}, {"Pooled", 1} } {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("AddMethodCheck%v did not panic", args)
+				}
+			}()
+			sv.AddMethodCheck(args[0].(string), args[1:]...)	//nolint:forcetypeassert
+		}()
+	}
+}
+
+func TestAddMethodCheckEmptyPool(t *testing.T) {
+	// The hidden state is lost by the clone
+	sv := NewStructVerifierWith(
+		func() any { return newLedger(genericLen) },
+		func(x any) any { return cloneLedger(x, true, false) },
+		WithMethodCheck("Pooled"),
+	)
+
+	err := sv.Verify()
+	var mErr *ErrSVMethodDiverged
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone loses the pool")
+	case errors.As(err, &mErr):
+		if mErr.Method != "Pooled" || mErr.Field != "" {
+			t.Errorf("got method %q and field %q, want - %q and %q", mErr.Method, mErr.Field, "Pooled", "")
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVMethodDiverged", err, err)
+	}
+}
+
+func TestAddMethodCheckBorrowPool(t *testing.T) {
+	// The clone stores amounts in the hidden state of the original
+	sv := NewStructVerifierWith(
+		func() any { return newLedger(genericLen) },
+		func(x any) any { return cloneLedger(x, false, true) },
+		WithMethodCheck("Pooled"),
+	)
+
+	err := sv.Verify()
+	var mErr *ErrSVMethodDiverged
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone borrows the pool of the original")
+	case errors.As(err, &mErr):
+		if mErr.Method != "Pooled" || mErr.Field != "Amounts" {
+			t.Errorf("got method %q and field %q, want - %q and %q", mErr.Method, mErr.Field, "Pooled", "Amounts")
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVMethodDiverged", err, err)
+	}
+}
@@ -0,0 +1,144 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrSVNestedClone represents an error that occurs when the verification of the
+// Clone method of a nested structure fails, see [StructVerifier.NestedCloners]. Path
+// contains the path to the nested field from the verified structure type, Err
+// contains the error of the nested verification.
+type ErrSVNestedClone struct {
+	structVerifierError
+	Path	string
+	Err		error
+}
+
+// Unwrap returns the error of the nested verification
+func (e *ErrSVNestedClone) Unwrap() error {
+	return e.Err
+}
+
+/*
+NestedCloners enables the verification of the Clone methods of nested
+structures. After the verification of the structure itself,
+the types of its exported fields are walked recursively, and each structure
+type (or pointer to it) having the Clone method that returns the same type,
+either T or *T, is verified by its own [StructVerifier] configured the same
+way as the parent one (except user-defined accessors and hooks):
+
+  type Config struct {
+      Server  *ServerConfig   // has Clone() *ServerConfig
+      Limits  []LimitsConfig  // has Clone() LimitsConfig
+  }
+
+Structures are found inside pointers, slices, arrays and map values. Each
+nested type is verified once. If the verification of a nested type fails,
+the *[ErrSVNestedClone] error with the path to the nested field is returned.
+*/
+func (sv *StructVerifier) NestedCloners() *StructVerifier {
+	sv.nestedCloners = true
+	return sv
+}
+
+// verifyNested verifies Clone methods of the structures nested into the verified structure
+func (sv *StructVerifier) verifyNested() error {
+	t := reflect.TypeOf(sv.creator()).Elem()
+
+	return sv.walkNested(t, t.String(), map[reflect.Type]bool{t: true})
+}
+
+// walkNested walks through the exported fields of the structure type t and verifies
+// Clone methods of nested structures, the path is the type path to t
+func (sv *StructVerifier) walkNested(t reflect.Type, path string, seen map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !isExported(f.Name) {
+			continue
+		}
+
+		st := nestedStruct(f.Type)
+		if st == nil || seen[st] {
+			continue
+		}
+		seen[st] = true
+
+		fPath := path + "." + f.Name
+		if cloner, ok := cloneMethod(st); ok {
+			nsv := *sv
+			nsv.creator = func() any { return reflect.New(st).Interface() }
+			nsv.cloner = cloner
+			nsv.nestedCloners = false
+			nsv.accessors = nil
+			nsv.sharedFields = nil
+			nsv.onFieldStart, nsv.onFieldDone = nil, nil
+
+			if err := nsv.Verify(); err != nil {
+				return &ErrSVNestedClone{
+					structVerifierError:	newErrSV("verification of the Clone method of the nested" +
+												" structure %s (%v) failed: %w", fPath, st, err),
+					Path:					fPath,
+					Err:					err,
+				}
+			}
+		}
+
+		if err := sv.walkNested(st, fPath, seen); err != nil {
+			return err
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// nestedStruct returns the structure type contained in t directly or via
+// pointers, slices, arrays and map values, or nil if t does not contain it
+func nestedStruct(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() { //nolint:exhaustive	// other kinds cannot contain structures
+		case reflect.Struct:
+			if isAtomic(t) {
+				return nil
+			}
+			return t
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+// cloneMethod returns the cloner function that calls the Clone method of the
+// structure type t, if t has the Clone method returning T or *T
+func cloneMethod(t reflect.Type) (ClonerFunc, bool) {
+	m, ok := reflect.PointerTo(t).MethodByName("Clone")
+	// The first input argument is the receiver
+	if !ok || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 {
+		return nil, false
+	}
+
+	out := m.Type.Out(0)
+	if out != t && out != reflect.PointerTo(t) {
+		return nil, false
+	}
+
+	return func(x any) any {
+		v := reflect.ValueOf(x)
+		if v.Type() != reflect.PointerTo(t) {
+			panic(fmt.Sprintf("unsupported type to clone: got - %T, want - %v", x, reflect.PointerTo(t)))
+		}
+
+		rv := v.MethodByName("Clone").Call(nil)[0]
+		if out == t {
+			// Return the pointer to the cloned value
+			p := reflect.New(t)
+			p.Elem().Set(rv)
+			rv = p
+		}
+
+		return rv.Interface()
+	}, true
+}
@@ -0,0 +1,88 @@
+package clone
+
+import (
+	"errors"
+	"testing"
+)
+
+type testTLSConfig struct {
+	Ciphers	[]string
+}
+
+// Clone shares ciphers with the original if shareCiphers is set
+func (c testTLSConfig) Clone() testTLSConfig {
+	if !shareCiphers {
+		c.Ciphers = append([]string(nil), c.Ciphers...)
+	}
+	return c
+}
+
+// shareCiphers makes testTLSConfig.Clone incorrect
+var shareCiphers bool
+
+type testServerConfig struct {
+	Addr	string
+	TLS		*testTLSConfig
+}
+
+func (c *testServerConfig) Clone() *testServerConfig {
+	rv := *c
+	if c.TLS != nil {
+		// Copy the TLS configuration without its Clone method
+		tls := testTLSConfig{Ciphers: append([]string(nil), c.TLS.Ciphers...)}
+		rv.TLS = &tls
+	}
+	return &rv
+}
+
+type testAppConfig struct {
+	Name	string
+	Servers	[]*testServerConfig
+}
+
+func (c *testAppConfig) Clone() *testAppConfig {
+	rv := *c
+	rv.Servers = make([]*testServerConfig, 0, len(c.Servers))
+	for _, s := range c.Servers {
+		rv.Servers = append(rv.Servers, s.Clone())
+	}
+	return &rv
+}
+
+func TestNestedCloners(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testAppConfig{} },
+		func(x any) any { return x.(*testAppConfig).Clone() },	//nolint:forcetypeassert
+		WithNestedCloners(),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of nested cloners failed: %v", err)
+	}
+}
+
+func TestNestedClonersShared(t *testing.T) {
+	// The parent Clone methods do not use the incorrect nested Clone method,
+	// so only the nested verification can detect it
+	shareCiphers = true
+	defer func() { shareCiphers = false }()
+
+	sv := NewStructVerifierWith(
+		func() any { return &testAppConfig{} },
+		func(x any) any { return x.(*testAppConfig).Clone() },	//nolint:forcetypeassert
+		WithNestedCloners(),
+	)
+
+	err := sv.Verify()
+
+	var errNested *ErrSVNestedClone
+	if !errors.As(err, &errNested) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVNestedClone", err, err)
+	}
+	if want := "clone.testAppConfig.Servers.TLS"; errNested.Path != want {
+		t.Errorf("got nested path %q, want - %q", errNested.Path, want)
+	}
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("nested error does not contain *ErrSVOrigChanged: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package clone
+
+import "reflect"
+
+// The names of the optional fields verification passes
+const (
+	optionalPopulated	= "populated"
+	optionalAbsent		= "absent"
+)
+
+// ErrSVOptional represents an error that occurs during the verification of
+// optional fields, see [StructVerifier.RegisterOptional]. Field contains the
+// name of the field, Pass contains the name of the failed pass: "populated"
+// or "absent". The error of the pass, like *[ErrSVOrigChanged], is wrapped.
+type ErrSVOptional struct {
+	structVerifierError
+	Field	string
+	Pass	string
+}
+
+// ErrSVOptionalMismatch represents an error that occurs when the optional field
+// of the clone is absent while it is populated in the original, or vice versa.
+// Field contains the name of the field.
+type ErrSVOptionalMismatch struct {
+	structVerifierError
+	Field	string
+}
+
+// optional describes the optional semantics of a type
+type optional struct {
+	populate	func() any
+	isAbsent	func(v any) bool
+}
+
+/*
+RegisterOptional registers the type t as optional: values of the type may be
+present or absent, like pointers to optional settings or user-defined
+Optional[T] types. The populate function returns a meaningful non-zero value
+of type t, it must return equal values on each call. The isAbsent function
+recognizes the absent state of the value, the zero value of t is used as the
+absent value. For example:
+
+  sv.RegisterOptional(reflect.TypeOf((*Limits)(nil)),
+      func() any { return &Limits{Max: 10, Allowed: []string{"a"}} },
+      func(v any) bool { return v.(*Limits) == nil },
+  )
+
+For each exported field of the registered type, [StructVerifier.Verify]
+performs two additional passes:
+
+  - populated - the field of the filled original is set to the value produced
+    by populate, the field of the clone must not be absent, must not share
+    pointers with the original and must be independent of it, as by the
+    per-field verification
+  - absent - the field of the filled original is set to the zero value, the
+    field of the clone must be absent too
+
+Failures are reported by *[ErrSVOptional] naming the field and the pass, it
+wraps the error of the pass, like *[ErrSVOptionalMismatch] when the clone
+field is absent in the populated pass or present in the absent pass.
+Registering the type again replaces the previous functions.
+*/
+func (sv *StructVerifier) RegisterOptional(t reflect.Type, populate func() any, isAbsent func(v any) bool) *StructVerifier {
+	if sv.optionals == nil {
+		sv.optionals = map[reflect.Type]optional{}
+	}
+	sv.optionals[t] = optional{populate: populate, isAbsent: isAbsent}
+
+	return sv
+}
+
+// verifyOptional verifies both passes for the fields of registered optional types
+func (sv *StructVerifier) verifyOptional(fields []string) error {
+	t := reflect.TypeOf(sv.creator()).Elem()
+	for _, field := range fields {
+		f, ok := typeFieldByPath(t, field)
+		if !ok {
+			continue
+		}
+		opt, ok := sv.optionals[f.Type]
+		if !ok {
+			continue
+		}
+
+		if err := sv.verifyPopulated(field, opt); err != nil {
+			return &ErrSVOptional{
+				structVerifierError:	newErrSV("optional field %q, %s pass: %w", field, optionalPopulated, err),
+				Field:					field,
+				Pass:					optionalPopulated,
+			}
+		}
+
+		if err := sv.verifyAbsent(field, opt); err != nil {
+			return &ErrSVOptional{
+				structVerifierError:	newErrSV("optional field %q, %s pass: %w", field, optionalAbsent, err),
+				Field:					field,
+				Pass:					optionalAbsent,
+			}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// verifyPopulated checks the clone of the original with the populated optional field
+func (sv *StructVerifier) verifyPopulated(field string, opt optional) error {
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		return err
+	}
+
+	for _, x := range []any{orig, ref} {
+		f := fieldByPath(reflect.ValueOf(x).Elem(), field)
+		val := reflect.ValueOf(opt.populate())
+		if !val.IsValid() || !val.Type().AssignableTo(f.Type()) {
+			return &ErrSVSetterTypeMismatch{newErrSV("populate function returned value of type %T," +
+				" that cannot be assigned to the field of type %v", opt.populate(), f.Type())}
+		}
+		f.Set(val)
+	}
+
+	if !sv.equal(orig, ref) {
+		return &ErrSVRefOrigEqual{newErrSV("populate function returned different values: orig - %s, ref - %s",
+			sv.dump(orig), sv.dump(ref))}
+	}
+
+	clone, err := sv.callCloner(orig, field)
+	if err != nil {
+		return err
+	}
+
+	if !sv.equal(orig, clone) {
+		return &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
+			" orig - %s, clone - %s", sv.dump(orig), sv.dump(clone))}
+	}
+
+	if cf := fieldByPath(reflect.ValueOf(clone).Elem(), field); opt.isAbsent(cf.Interface()) {
+		return &ErrSVOptionalMismatch{
+			structVerifierError:	newErrSV("CLONE field %q is ABSENT, but it is populated in the ORIGINAL: %s",
+				field, sv.dump(orig)),
+			Field:					field,
+		}
+	}
+
+	if err := sv.checkShared(orig, clone, field); err != nil {
+		return err
+	}
+
+	if err := sv.autoChange(clone, field); err != nil {
+		return &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field,  err)}
+	}
+
+	if !sv.equal(orig, ref) {
+		return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+			" at %q after the CLONE FIELD ----> %q <---- has been CHANGED, clone: %s",
+			sv.dump(orig), sv.dump(ref), sv.origDiff(orig, ref), field, sv.dump(clone))}
+	}
+
+	// OK
+	return nil
+}
+
+// verifyAbsent checks the clone of the original with the absent optional field
+func (sv *StructVerifier) verifyAbsent(field string, opt optional) error {
+	orig, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	f := fieldByPath(reflect.ValueOf(orig).Elem(), field)
+	f.Set(reflect.Zero(f.Type()))
+
+	clone, err := sv.callCloner(orig, field)
+	if err != nil {
+		return err
+	}
+
+	if !sv.equal(orig, clone) {
+		return &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
+			" orig - %s, clone - %s", sv.dump(orig), sv.dump(clone))}
+	}
+
+	if cf := fieldByPath(reflect.ValueOf(clone).Elem(), field); !opt.isAbsent(cf.Interface()) {
+		return &ErrSVOptionalMismatch{
+			structVerifierError:	newErrSV("CLONE field %q must be ABSENT as in the ORIGINAL, but has value %s",
+				field, sv.dump(cf.Interface())),
+			Field:					field,
+		}
+	}
+
+	// OK
+	return nil
+}
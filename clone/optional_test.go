@@ -0,0 +1,129 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testLimits struct {
+	Max		int
+	Allowed	[]string
+}
+
+type testService struct {
+	Name	string
+	Limits	*testLimits
+}
+
+// cloneService returns a clone of testService, the clone gets empty limits
+// instead of nil if allocate is set, the limits with a single allowed item
+// are shared with the original if shareSingle is set
+func cloneService(x any, allocate, shareSingle bool) any {
+	orig := x.(*testService)	//nolint:forcetypeassert
+	rv := &testService{Name: orig.Name}
+
+	switch {
+	case orig.Limits == nil:
+		if allocate {
+			rv.Limits = &testLimits{}
+		}
+	case shareSingle && len(orig.Limits.Allowed) == 1:
+		rv.Limits = orig.Limits
+	default:
+		rv.Limits = &testLimits{Max: orig.Limits.Max, Allowed: append([]string(nil), orig.Limits.Allowed...)}
+	}
+
+	return rv
+}
+
+func TestRegisterOptional(t *testing.T) {
+	limitsType := reflect.TypeOf((*testLimits)(nil))
+	populate := func() any { return &testLimits{Max: 10, Allowed: []string{"a"}} }
+	isAbsent := func(v any) bool { return v.(*testLimits) == nil }	//nolint:forcetypeassert
+
+	sv := NewStructVerifier(
+		func() any { return &testService{} },
+		func(x any) any { return cloneService(x, false, false) },
+	).RegisterOptional(limitsType, populate, isAbsent)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the clone with optional field failed: %v", err)
+	}
+
+	// The clone shares the populated limits with the original
+	sv = NewStructVerifier(
+		func() any { return &testService{} },
+		func(x any) any { return cloneService(x, false, true) },
+	).RegisterOptional(limitsType, populate, isAbsent)
+
+	err := sv.Verify()
+	var errOpt *ErrSVOptional
+	switch {
+	case err == nil:
+		t.Errorf("verification of the clone sharing the populated field must fail")
+	case errors.As(err, &errOpt):
+		if errOpt.Field != "Limits" || errOpt.Pass != "populated" {
+			t.Errorf("got field %q and pass %q, want - %q and %q", errOpt.Field, errOpt.Pass, "Limits", "populated")
+		}
+		if !errors.As(err, new(*ErrSVSharedPointer)) {
+			t.Errorf("error %v does not wrap *ErrSVSharedPointer", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOptional", err, err)
+	}
+
+	// The clone allocates the absent limits
+	sv = NewStructVerifier(
+		func() any { return &testService{} },
+		func(x any) any { return cloneService(x, true, false) },
+	).RegisterOptional(limitsType, populate, isAbsent)
+
+	err = sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("verification of the clone allocating the absent field must fail")
+	case errors.As(err, &errOpt):
+		if errOpt.Field != "Limits" || errOpt.Pass != "absent" {
+			t.Errorf("got field %q and pass %q, want - %q and %q", errOpt.Field, errOpt.Pass, "Limits", "absent")
+		}
+		if !errors.As(err, new(*ErrSVCloneOrigNotEqual)) {
+			t.Errorf("error %v does not wrap *ErrSVCloneOrigNotEqual", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOptional", err, err)
+	}
+
+	// The clone drops the limits, the comparator treats absent limits as equal to any
+	sv = NewStructVerifier(
+		func() any { return &testService{} },
+		func(x any) any { return &testService{Name: x.(*testService).Name} },	//nolint:forcetypeassert
+	).RegisterOptional(limitsType, populate, isAbsent).
+		RegisterFieldComparator("Limits", func(a, b any) bool {
+			la, lb := a.(*testLimits), b.(*testLimits)	//nolint:forcetypeassert
+			return la == nil || lb == nil || la.Max == lb.Max
+		}).
+		AddChangers(func(v reflect.Value) bool {
+			if v.Type() != limitsType {
+				return false
+			}
+			v.Set(reflect.ValueOf(&testLimits{Max: 20}))
+			return true
+		})
+
+	err = sv.Verify()
+	var errMismatch *ErrSVOptionalMismatch
+	switch {
+	case err == nil:
+		t.Errorf("verification of the clone dropping the populated field must fail")
+	case errors.As(err, &errMismatch):
+		if errMismatch.Field != "Limits" {
+			t.Errorf("got field %q, want - %q", errMismatch.Field, "Limits")
+		}
+		if !errors.As(err, &errOpt) || errOpt.Pass != "populated" {
+			t.Errorf("error %v is not reported by the populated pass", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOptionalMismatch", err, err)
+	}
+}
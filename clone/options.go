@@ -0,0 +1,324 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Option defines the type of function that configures the [StructVerifier]
+// created by [NewStructVerifierWith].
+type Option func(sv *StructVerifier)
+
+/*
+NewStructVerifierWith works like [NewStructVerifier], but also applies the
+options to the created StructVerifier. It is a single configuration point for
+the verifier, instead of chaining the configuration methods:
+
+  sv := clone.NewStructVerifierWith(creator, cloner,
+      clone.WithSetters(intSliceSetter),
+      clone.WithChangers(intSliceChanger),
+      clone.WithMaxDepth(3),
+  )
+
+The configuration methods can still be called on the returned verifier.
+*/
+func NewStructVerifierWith(creator CreatorFunc, cloner ClonerFunc, opts ...Option) *StructVerifier {
+	sv := NewStructVerifier(creator, cloner)
+	for _, opt := range opts {
+		opt(sv)
+	}
+
+	return sv
+}
+
+// WithSetters returns an option that adds user-defined [SetterCreator]
+// functions, see [StructVerifier.AddSetters].
+func WithSetters(setters ...SetterCreator) Option {
+	return func(sv *StructVerifier) {
+		sv.AddSetters(setters...)
+	}
+}
+
+// WithChangers returns an option that adds user-defined [Changer]
+// functions, see [StructVerifier.AddChangers].
+func WithChangers(changers ...Changer) Option {
+	return func(sv *StructVerifier) {
+		sv.AddChangers(changers...)
+	}
+}
+
+// WithSeed returns an option that sets the initial value used by the embedded
+// Setter functions to generate field values. Different seeds produce different
+// values of fields, the seed must not be negative. Default seed is 0.
+func WithSeed(seed int) Option {
+	if seed < 0 {
+		panic(fmt.Sprintf("WithSeed: negative seed value %d", seed))
+	}
+
+	return func(sv *StructVerifier) {
+		sv.state = newSeedState(seed)
+	}
+}
+
+// WithMaxDepth returns an option that sets the maximum pointer indirection depth
+// of fields that are filled and changed automatically. Default depth is 8.
+func WithMaxDepth(depth int) Option {
+	if depth < 1 {
+		panic(fmt.Sprintf("WithMaxDepth: invalid depth value %d, must be greater than 0", depth))
+	}
+
+	return func(sv *StructVerifier) {
+		sv.maxDepth = depth
+	}
+}
+
+/*
+WithParallelism returns an option that sets the number of fields verified
+concurrently. Each concurrent worker uses its own original and reference
+objects, so the cloner function, Setter and Changer functions must be safe for
+concurrent use if n is greater than 1. Default is 1 - the fields are verified
+one by one.
+
+Regardless of the parallelism, the error of the first field (in the order of
+the structure declaration) that failed verification is returned.
+*/
+func WithParallelism(n int) Option {
+	if n < 1 {
+		panic(fmt.Sprintf("WithParallelism: invalid number of workers %d, must be greater than 0", n))
+	}
+
+	return func(sv *StructVerifier) {
+		sv.parallelism = n
+	}
+}
+
+// WithStrictSetters returns an option that enables the strict setters mode,
+// see [StructVerifier.StrictSetters].
+func WithStrictSetters() Option {
+	return func(sv *StructVerifier) {
+		sv.StrictSetters()
+	}
+}
+
+// WithChangeAllFields returns an option that enables the verification phase
+// with all fields changed at once, see [StructVerifier.ChangeAllFields].
+func WithChangeAllFields() Option {
+	return func(sv *StructVerifier) {
+		sv.ChangeAllFields()
+	}
+}
+
+// WithMaxErrorLen returns an option that sets the maximum length of values
+// rendered in error messages, see [StructVerifier.SetMaxErrorLen].
+func WithMaxErrorLen(n int) Option {
+	if n < 0 {
+		panic(fmt.Sprintf("WithMaxErrorLen: negative length %d", n))
+	}
+
+	return func(sv *StructVerifier) {
+		sv.SetMaxErrorLen(n)
+	}
+}
+
+// WithCheckNilInterfaces returns an option that enables the verification phase
+// with nil interface fields, see [StructVerifier.CheckNilInterfaces].
+func WithCheckNilInterfaces() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckNilInterfaces()
+	}
+}
+
+// WithAccessor returns an option that adds a user-defined accessor of the value
+// reachable through the public API, see [StructVerifier.AddAccessor].
+func WithAccessor(name string, get func(any) any, mutate func(any)) Option {
+	return func(sv *StructVerifier) {
+		sv.AddAccessor(name, get, mutate)
+	}
+}
+
+// WithNestedCloners returns an option that enables the verification of the
+// Clone methods of nested structures, see [StructVerifier.NestedCloners].
+func WithNestedCloners() Option {
+	return func(sv *StructVerifier) {
+		sv.NestedCloners()
+	}
+}
+
+// WithComparator returns an option that registers the user-defined function to
+// compare values of type T, see [StructVerifier.RegisterComparator].
+func WithComparator[T any](eq func(a, b T) bool) Option {
+	return func(sv *StructVerifier) {
+		sv.RegisterComparator(reflect.TypeOf((*T)(nil)).Elem(), func(a, b any) bool {
+			return eq(a.(T), b.(T))	//nolint:forcetypeassert	// only values of type T are passed
+		})
+	}
+}
+
+// WithFieldComparator returns an option that registers the user-defined function
+// to compare values of the field, see [StructVerifier.RegisterFieldComparator].
+func WithFieldComparator(field string, eq func(a, b any) bool) Option {
+	return func(sv *StructVerifier) {
+		sv.RegisterFieldComparator(field, eq)
+	}
+}
+
+// WithInsertMapKeys returns an option that enables the verification phase
+// with key insertion into maps, see [StructVerifier.InsertMapKeys].
+func WithInsertMapKeys() Option {
+	return func(sv *StructVerifier) {
+		sv.InsertMapKeys()
+	}
+}
+
+// WithAllowPartial returns an option that enables the partial mode of
+// verification, see [StructVerifier.AllowPartial].
+func WithAllowPartial() Option {
+	return func(sv *StructVerifier) {
+		sv.AllowPartial()
+	}
+}
+
+// WithSettersWithField returns an option that adds user-defined
+// [SetterWithFieldCreator] functions, see [StructVerifier.AddSettersWithField].
+func WithSettersWithField(setters ...SetterWithFieldCreator) Option {
+	return func(sv *StructVerifier) {
+		sv.AddSettersWithField(setters...)
+	}
+}
+
+// WithExpectShared returns an option that marks the fields as intentionally
+// shared between the clone and the original, see [StructVerifier.ExpectShared].
+func WithExpectShared(fields ...string) Option {
+	return func(sv *StructVerifier) {
+		sv.ExpectShared(fields...)
+	}
+}
+
+// WithMapChangeAllEntries returns an option that sets whether all entries of
+// map fields are changed, see [StructVerifier.SetMapChangeAllEntries].
+func WithMapChangeAllEntries(all bool) Option {
+	return func(sv *StructVerifier) {
+		sv.SetMapChangeAllEntries(all)
+	}
+}
+
+// WithCheckSparseFields returns an option that enables the verification phase
+// with partially populated structures, see [StructVerifier.CheckSparseFields].
+func WithCheckSparseFields() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckSparseFields()
+	}
+}
+
+// WithDiffer returns an option that makes the verifier compare values by
+// the external comparison library, see [StructVerifier.UseDiffer].
+func WithDiffer(d Differ) Option {
+	return func(sv *StructVerifier) {
+		sv.UseDiffer(d)
+	}
+}
+
+// WithCaptureRepro returns an option that enables capturing of snippets
+// reproducing failures, see [StructVerifier.CaptureRepro].
+func WithCaptureRepro() Option {
+	return func(sv *StructVerifier) {
+		sv.CaptureRepro()
+	}
+}
+
+// WithCheckEmptyCapacity returns an option that enables the verification phase
+// with empty slices of nonzero capacity, see [StructVerifier.CheckEmptyCapacity].
+func WithCheckEmptyCapacity() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckEmptyCapacity()
+	}
+}
+
+// WithMapSliceValues returns an option that makes the verifier fill fields of
+// the map[string]any type with slice values, see [StructVerifier.SetMapSliceValues].
+func WithMapSliceValues() Option {
+	return func(sv *StructVerifier) {
+		sv.SetMapSliceValues(true)
+	}
+}
+
+// WithMapNestedValues returns an option that sets the number of levels of maps
+// nested into map[string]any fields, see [StructVerifier.SetMapNestedValues].
+func WithMapNestedValues(depth int) Option {
+	return func(sv *StructVerifier) {
+		sv.SetMapNestedValues(depth)
+	}
+}
+
+// WithShuffleFields returns an option that makes the verifier process fields
+// in the random order determined by the seed, see [StructVerifier.SetShuffleFields].
+func WithShuffleFields(seed int64) Option {
+	return func(sv *StructVerifier) {
+		sv.SetShuffleFields(seed)
+	}
+}
+
+// WithCheckNilReceiver returns an option that enables the verification phase
+// calling the cloner with the nil pointer, see [StructVerifier.CheckNilReceiver].
+func WithCheckNilReceiver() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckNilReceiver()
+	}
+}
+
+// WithCheckNilPointers returns an option that enables the verification phase
+// setting pointers of the clone to nil, see [StructVerifier.CheckNilPointers].
+func WithCheckNilPointers() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckNilPointers()
+	}
+}
+
+// WithCheckCrossFieldAliasing returns an option that enables the verification
+// phase detecting slices of the clone sharing backing arrays, see
+// [StructVerifier.CheckCrossFieldAliasing].
+func WithCheckCrossFieldAliasing() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckCrossFieldAliasing()
+	}
+}
+
+// WithCheckDeterministic returns an option that enables the verification phase
+// comparing clones produced by several calls, see [StructVerifier.CheckDeterministic].
+func WithCheckDeterministic() Option {
+	return func(sv *StructVerifier) {
+		sv.CheckDeterministic()
+	}
+}
+
+// WithNonFiniteFloats returns an option that makes the verifier fill float
+// fields with NaN and Inf values, see [StructVerifier.FillNonFiniteFloats].
+func WithNonFiniteFloats() Option {
+	return func(sv *StructVerifier) {
+		sv.FillNonFiniteFloats()
+	}
+}
+
+// WithExternal returns an option that registers the structure type defined in
+// another package, see [StructVerifier.RegisterExternal].
+func WithExternal(t reflect.Type, producer ConcreteProducer, cloner ClonerFunc) Option {
+	return func(sv *StructVerifier) {
+		sv.RegisterExternal(t, producer, cloner)
+	}
+}
+
+// WithAliasCheck returns an option that adds the user-defined aliasing check
+// of values of the type, see [StructVerifier.AddAliasCheck].
+func WithAliasCheck(fieldType reflect.Type, check func(origField, cloneField reflect.Value) error) Option {
+	return func(sv *StructVerifier) {
+		sv.AddAliasCheck(fieldType, check)
+	}
+}
+
+// WithMethodCheck returns an option that registers the exported method called
+// on the original and clones, see [StructVerifier.AddMethodCheck].
+func WithMethodCheck(name string, args ...any) Option {
+	return func(sv *StructVerifier) {
+		sv.AddMethodCheck(name, args...)
+	}
+}
@@ -0,0 +1,125 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewStructVerifierWith(t *testing.T) {
+	optSets := map[string][]Option{
+		"default":		nil,
+		"seed":			{WithSeed(10)},
+		"parallel":		{WithParallelism(3)},
+		"all":			{WithSeed(3), WithMaxDepth(2), WithParallelism(2)},
+	}
+
+	for name, opts := range optSets {
+		sv := NewStructVerifierWith(
+			func() any { return newTestComplexStruct() },
+			func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+			append([]Option{WithSetters(intSliceSetter), WithChangers(intSliceChanger)}, opts...)...,
+		)
+
+		if err := sv.Verify(); err != nil {
+			t.Errorf("[%s] verification failed: %v", name, err)
+		}
+	}
+}
+
+func TestNewStructVerifierWithShallow(t *testing.T) {
+	optSets := map[string][]Option{
+		"default":		nil,
+		"seed":			{WithSeed(10)},
+		"parallel":		{WithParallelism(3)},
+		"all":			{WithSeed(3), WithMaxDepth(2), WithParallelism(2)},
+	}
+
+	for name, opts := range optSets {
+		sv := NewStructVerifierWith(
+			func() any { return newTestComplexStruct() },
+			func(x any) any { rv := *x.(*testComplexStruct); return &rv },	//nolint:forcetypeassert
+			append([]Option{WithSetters(intSliceSetter), WithChangers(intSliceChanger)}, opts...)...,
+		)
+
+		err := sv.Verify()
+		switch {
+		case err == nil:
+			t.Errorf("[%s] returned no error but must fail, because clone shares slices and maps", name)
+		case errors.As(err, new(*ErrSVOrigChanged)):
+			// OK, expected error
+		default:
+			t.Errorf("[%s] got unexpected error %T (%v), want - *ErrSVOrigChanged", name, err, err)
+		}
+	}
+}
+
+func TestWithSeed(t *testing.T) {
+	fill := func(seed int) *testComplexStruct {
+		filled, err := NewStructVerifierWith(
+			func() any { return newTestComplexStruct() },
+			func(x any) any { return x },
+			WithSetters(intSliceSetter), WithSeed(seed),
+		).autoFill()
+		if err != nil {
+			t.Fatalf("cannot autofill with seed %d: %v", seed, err)
+		}
+
+		return filled.(*testComplexStruct)	//nolint:forcetypeassert
+	}
+
+	if s0, s5 := fill(0), fill(5); s0.Int64param == s5.Int64param {
+		t.Errorf("different seeds produced the same value of Int64param - %d", s0.Int64param)
+	}
+}
+
+func TestParallelFirstError(t *testing.T) {
+	// With any parallelism, the error of the first field must be returned
+	for n := 1; n <= 4; n++ {
+		err := NewStructVerifierWith(
+			func() any { return newTestComplexStruct() },
+			func(x any) any { rv := *x.(*testComplexStruct); return &rv },	//nolint:forcetypeassert
+			WithSetters(intSliceSetter), WithChangers(intSliceChanger), WithParallelism(n),
+		).Verify()
+
+		if err == nil {
+			t.Fatalf("[%d] returned no error but must fail", n)
+		}
+		if want := `"IntList"`; !strings.Contains(err.Error(), want) {
+			t.Errorf("[%d] error does not relate to the first failed field %s: %v", n, want, err)
+		}
+	}
+}
+
+func TestParallelPartial(t *testing.T) {
+	type legacyStruct struct {
+		Names	[]string
+		Flag	bool			// unsupported type to set
+		Ch		chan int		// unsupported type to set
+		Count	int
+	}
+
+	// Workers must not modify the verifier, run with -race to detect it
+	sv := NewStructVerifierWith(
+		func() any { return &legacyStruct{} },
+		func(x any) any {
+			orig := x.(*legacyStruct)	//nolint:forcetypeassert
+			rv := *orig
+			rv.Names = append([]string(nil), orig.Names...)
+			return &rv
+		},
+		WithParallelism(4),
+	).AllowPartial()
+
+	if err := sv.Verify(); err != nil {
+		t.Fatalf("partial parallel verification failed: %v", err)
+	}
+
+	if want := []string{"Ch", "Flag"}; !reflect.DeepEqual(sv.SkippedFields(), want) {
+		t.Errorf("got skipped fields %v, want - %v", sv.SkippedFields(), want)
+	}
+	if len(sv.Warnings()) != 2 {
+		t.Errorf("got unexpected warnings: %v", sv.Warnings())
+	}
+}
@@ -0,0 +1,56 @@
+package clone
+
+import (
+	"sync"
+)
+
+// verifyParallel verifies fields concurrently using sv.parallelism workers. It
+// returns the error of the first failed field in the order of the fields list.
+// Workers share the verifier and must only read it
+func (sv *StructVerifier) verifyParallel(fields []string) error {
+	errs := make([]error, len(fields))
+	next := make(chan int)
+
+	// Each worker uses its own original and reference values, so modifications
+	// of clones do not affect other workers. The values are filled here, before
+	// the fan-out, because filling records the reached seed state, the fields
+	// skipped in the partial mode and their warnings in the verifier
+	type values struct {
+		orig, ref	any
+	}
+	vals := make([]values, sv.parallelism)
+	for w := range vals {
+		orig, ref, err := sv.fillOrigRef()
+		if err != nil {
+			return err
+		}
+		vals[w] = values{orig, ref}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < sv.parallelism; w++ {
+		wg.Add(1)
+		go func(v values) {
+			defer wg.Done()
+
+			for i := range next {
+				errs[i] = sv.verifyField(v.orig, v.ref, fields[i])
+			}
+		}(vals[w])
+	}
+
+	for i := range fields {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// OK
+	return nil
+}
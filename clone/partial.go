@@ -0,0 +1,85 @@
+package clone
+
+import (
+	"sort"
+	"strings"
+)
+
+// ErrSVFieldSkipped represents a warning that the field has been skipped in
+// the partial mode, because its value cannot be set or changed, see
+// [StructVerifier.AllowPartial]. Field contains the name of the skipped field.
+type ErrSVFieldSkipped struct {
+	structVerifierError
+	Field	string
+}
+
+/*
+AllowPartial enables the partial mode of verification. In this mode, fields of
+types not supported by the Setter or Changer functions do not abort the
+verification with the *[ErrSVOrigFill] or *[ErrSVChange] errors, but are skipped
+and keep their initial values created by the creator function. The rest of the
+fields are verified as usual.
+
+Each skipped field is reported by the *[ErrSVFieldSkipped] warning, see
+[StructVerifier.Warnings], the names of skipped fields are also returned by
+[StructVerifier.SkippedFields]. It allows adopting the verification for large
+structures incrementally, adding Setter and Changer functions over time.
+*/
+func (sv *StructVerifier) AllowPartial() *StructVerifier {
+	sv.partial = true
+	return sv
+}
+
+// SkippedFields returns the sorted list of fields skipped by the last call of
+// [StructVerifier.Verify] in the partial mode, see [StructVerifier.AllowPartial].
+func (sv *StructVerifier) SkippedFields() []string {
+	fields := make([]string, 0, len(sv.skipped))
+	for field := range sv.skipped {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// skipField marks the field as skipped in the partial mode, err is the reason
+func (sv *StructVerifier) skipField(field string, err error) {
+	if sv.skipped == nil {
+		sv.skipped = map[string]bool{}
+	}
+	sv.skipped[field] = true
+	sv.warnings = append(sv.warnings, &ErrSVFieldSkipped{
+		structVerifierError:	newErrSV("field %q has been skipped: %w", field, err),
+		Field:					field,
+	})
+}
+
+// skipUnchangeable probes changing of each field of the filled structure and
+// skips the fields that cannot be changed
+func (sv *StructVerifier) skipUnchangeable() error {
+	probe, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill probe structure: %w", err)}
+	}
+
+	for _, field := range sv.changeableFields(sv.verifiedFields()) {
+		if err := sv.autoChange(probe, field); err != nil {
+			sv.skipField(field, err)
+		}
+	}
+
+	return nil
+}
+
+// verifiedFields returns the list of fields to verify, see structFields, the
+// skipped fields and the promoted fields of skipped embedded structures are excluded
+func (sv *StructVerifier) verifiedFields() []string {
+	var fields []string
+	for _, path := range structFields(sv.creator()) {
+		if !sv.skipped[path] && !sv.skipped[strings.SplitN(path, ".", 2)[0]] {
+			fields = append(fields, path)
+		}
+	}
+
+	return fields
+}
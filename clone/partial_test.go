@@ -0,0 +1,50 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testLegacy struct {
+	Names	[]string
+	Flag	bool			// unsupported type to set
+	Ch		chan int		// unsupported type to set
+	Count	int
+}
+
+func cloneLegacy(x any) any {
+	orig := x.(*testLegacy)	//nolint:forcetypeassert
+	rv := *orig
+	rv.Names = append([]string(nil), orig.Names...)
+	return &rv
+}
+
+func TestAllowPartial(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testLegacy{} }, cloneLegacy).AllowPartial()
+
+	if err := sv.Verify(); err != nil {
+		t.Fatalf("partial verification failed: %v", err)
+	}
+
+	if want := []string{"Ch", "Flag"}; !reflect.DeepEqual(sv.SkippedFields(), want) {
+		t.Errorf("got skipped fields %v, want - %v", sv.SkippedFields(), want)
+	}
+	if len(sv.Warnings()) != 2 || !errors.As(sv.Warnings()[0], new(*ErrSVFieldSkipped)) {
+		t.Errorf("got unexpected warnings: %v", sv.Warnings())
+	}
+}
+
+func TestAllowPartialDisabled(t *testing.T) {
+	sv := NewStructVerifier(func() any { return &testLegacy{} }, cloneLegacy)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because structure has fields of unsupported types")
+	case errors.As(err, new(*ErrSVOrigFill)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigFill", err, err)
+	}
+}
@@ -0,0 +1,526 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+ChangeAllFields enables an additional verification phase performed after the
+verification of each field separately. In this phase, all fields of the clone
+are changed at once, then the original object is compared with the reference.
+
+It reveals cloning errors where several fields share the same storage, for
+example, two slices of the clone are sub-sliced from the same array. The
+separate verification of each field is performed first, so the precise
+per-field diagnostics is preserved if some field cannot pass it.
+*/
+func (sv *StructVerifier) ChangeAllFields() *StructVerifier {
+	sv.allFields = true
+	return sv
+}
+
+// verifyAllFields creates a clone of orig, changes all fields of the clone
+// and checks that the original is not changed
+func (sv *StructVerifier) verifyAllFields(orig, ref any, fields []string) error {
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	if !sv.equal(orig, clone) {
+		return &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
+			" orig - %s, clone - %s%s", sv.dump(orig), sv.dump(clone), sv.diffNote(orig, clone, "orig", "clone"))}
+	}
+
+	// Change all fields of the clone
+	for _, field := range fields {
+		if err := sv.autoChange(clone, field); err != nil {
+			return &ErrSVChange{newErrSV("cannot update field %q in the CLONE: %w", field,  err)}
+		}
+	}
+
+	// Compare the original and the reference - they should be the same
+	if !sv.equal(orig, ref) {
+		return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+			" at %q after ALL CLONE FIELDS have been CHANGED, clone: %s%s",
+			sv.dump(orig), sv.dump(ref), sv.origDiff(orig, ref), sv.dump(clone),
+			sv.diffNote(ref, orig, "ref", "orig"))}
+	}
+
+	// Compare the clone and the original structure - they should NOT be the same
+	if len(fields) != 0 && sv.equal(orig, clone) {
+		return &ErrSVCloneOrigEqual{newErrSV(
+			"ALL CLONE fields have been UPDATED but the clone is EQUAL the ORIGINAL value: %s", sv.dump(clone))}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+CheckNilInterfaces enables an additional verification phase for structures with
+interface fields. In the regular phases, interface fields are filled by the
+values of registered concrete types (see [StructVerifier.RegisterConcrete]) to
+check that the clone deep-copies them. In this phase, all exported interface
+fields of the filled original are set to nil, and the clone must leave them
+nil too, otherwise *[ErrSVNilIfaceNotPreserved] is returned. The interface
+fields of structures embedded by value are set to nil too, the fields of other
+nested structures are not reached.
+*/
+func (sv *StructVerifier) CheckNilInterfaces() *StructVerifier {
+	sv.nilIfaces = true
+	return sv
+}
+
+// verifyNilInterfaces creates the original with all exported interface
+// fields set to nil and checks that the clone preserves them nil
+func (sv *StructVerifier) verifyNilInterfaces() error {
+	orig, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	s := reflect.ValueOf(orig).Elem()
+	var ifaces []string
+	for _, field := range sv.verifiedFields() {
+		if f := fieldByPath(s, field); f.Kind() == reflect.Interface {
+			f.Set(reflect.Zero(f.Type()))
+			ifaces = append(ifaces, field)
+		}
+	}
+
+	if ifaces == nil {
+		// Nothing to check
+		return nil
+	}
+
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	cs := reflect.ValueOf(clone).Elem()
+	for _, field := range ifaces {
+		if cf := fieldByPath(cs, field); !cf.IsNil() {
+			return &ErrSVNilIfaceNotPreserved{newErrSV("CLONE field %q must be nil as in the ORIGINAL," +
+				" but has value %s", field, sv.dump(cf.Interface()))}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+CheckNilReceiver enables an additional verification phase for cloners that
+must handle the nil pointer to the structure, e.g. Clone methods written as
+
+  func (c *Config) Clone() *Config {
+      if c == nil {
+          return nil
+      }
+      ...
+  }
+
+In the regular phases, the cloner is called only for filled originals. In this
+phase, the cloner is called with the nil pointer to the structure and must
+return nil, either the untyped nil or the nil pointer of the same type.
+Otherwise, *[ErrSVNilReceiverNotNil] is returned. The panic of the cloner,
+e.g. caused by the type assertion or the dereference of the nil pointer, is
+reported as *[ErrSVClonePanic], see also [StructVerifier.PropagatePanics].
+*/
+func (sv *StructVerifier) CheckNilReceiver() *StructVerifier {
+	sv.nilReceiver = true
+	return sv
+}
+
+// verifyNilReceiver calls the cloner with the nil pointer to the structure
+// and checks that the cloner returns nil
+func (sv *StructVerifier) verifyNilReceiver() error {
+	nilPtr := reflect.Zero(reflect.TypeOf(sv.creator())).Interface()
+
+	clone, err := sv.safeCall(nilPtr, "")
+	if err != nil {
+		return err
+	}
+
+	if cv := reflect.ValueOf(clone); cv.IsValid() && (cv.Type() != reflect.TypeOf(nilPtr) || !cv.IsNil()) {
+		return &ErrSVNilReceiverNotNil{newErrSV("cloner function returned %s for the nil pointer %T," +
+			" but nil is expected", sv.dump(clone), nilPtr)}
+	}
+
+	// OK
+	return nil
+}
+
+// maxKeyAttempts is the maximum number of attempts to produce a key absent in the map
+const maxKeyAttempts = 100
+
+/*
+InsertMapKeys enables an additional verification phase for structures with map
+fields. The Changer functions modify the existing values of maps, but a clone
+sharing the map with the original is also revealed by the insertion of a new
+key. In this phase, a fresh key is inserted into each exported map field of
+the clone, including the fields of structures embedded by value, then the
+original must not contain the key. And vice versa, a fresh key inserted into
+the map of the original must not appear in the clone. Otherwise,
+*[ErrSVOrigChanged] with the offending key is returned.
+
+Fresh keys and their values are produced by the Setter functions, if the value
+cannot be produced, the zero value of the map value type is inserted. Maps
+expected to be shared, see [StructVerifier.ExpectShared], are not checked.
+*/
+func (sv *StructVerifier) InsertMapKeys() *StructVerifier {
+	sv.insertKeys = true
+	return sv
+}
+
+// verifyInsertKeys inserts fresh keys into map fields of the clone and the
+// original and checks that they do not appear in the other one
+func (sv *StructVerifier) verifyInsertKeys() error {
+	for _, cloneFirst := range []bool{true, false} {
+		orig, err := sv.autoFill()
+		if err != nil {
+			return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+		}
+
+		clone, err := sv.callCloner(orig, "")
+		if err != nil {
+			return err
+		}
+
+		// The map is changed in dst and checked in chk
+		dst, chk, dstName, chkName := reflect.ValueOf(clone).Elem(), reflect.ValueOf(orig).Elem(), "CLONE", "ORIGINAL"
+		if !cloneFirst {
+			dst, chk, dstName, chkName = chk, dst, chkName, dstName
+		}
+
+		fl := sv.newFiller()
+		for _, field := range sv.verifiedFields() {
+			dm, cm := fieldByPath(dst, field), fieldByPath(chk, field)
+			if dm.Kind() != reflect.Map || dm.IsNil() || sv.isShared(field) {
+				continue
+			}
+
+			key, err := fl.freshKey(dm, cm, field)
+			if err != nil {
+				return &ErrSVChange{newErrSV("cannot insert key into field %q of the %s: %w", field, dstName, err)}
+			}
+			val, err := fl.value(reflect.New(dm.Type().Elem()).Elem(), field)
+			if err != nil {
+				// Only the key matters, use the zero value
+				val = reflect.Zero(dm.Type().Elem())
+			}
+			dm.SetMapIndex(key, val)
+
+			if cm.MapIndex(key).IsValid() {
+				return &ErrSVOrigChanged{newErrSV("key %s inserted into the map field %q of the %s" +
+					" has APPEARED in the %s", sv.dump(key.Interface()), field, dstName, chkName)}
+			}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// freshKey produces a key of the map m1 that is absent in both maps m1 and m2
+func (fl *filler) freshKey(m1, m2 reflect.Value, path string) (reflect.Value, error) {
+	for i := 0; i < maxKeyAttempts; i++ {
+		key, err := fl.value(reflect.New(m1.Type().Key()).Elem(), path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if !m1.MapIndex(key).IsValid() && (m2.IsNil() || !m2.MapIndex(key).IsValid()) {
+			return key, nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("no fresh key produced after %d attempts", maxKeyAttempts)
+}
+
+/*
+CheckSparseFields enables an additional verification phase for cloners that
+may fail on partially populated structures. In the regular phases, all fields
+of the original are populated, so the cloner is never called for nil or empty
+fields. In this phase, the original has only one populated field at a time,
+all other fields keep the values created by the creator function, usually zero
+ones. The phase is repeated for each verified field.
+
+It reveals the cloners which, for example, index slices assuming they are not
+empty or dereference nil pointers. The panic of the cloner is reported as
+*[ErrSVClonePanic] (see also [StructVerifier.PropagatePanics]), the clone that
+differs from the sparse original is reported as *[ErrSVCloneOrigNotEqual].
+*/
+func (sv *StructVerifier) CheckSparseFields() *StructVerifier {
+	sv.sparse = true
+	return sv
+}
+
+// verifySparse creates originals with only one populated field from the
+// fields list and checks that their clones are equal to them
+func (sv *StructVerifier) verifySparse(fields []string) error {
+	filled, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	for _, field := range fields {
+		orig := sv.creator()
+		fieldByPath(reflect.ValueOf(orig).Elem(), field).Set(fieldByPath(reflect.ValueOf(filled).Elem(), field))
+
+		clone, err := sv.callCloner(orig, field)
+		if err != nil {
+			return err
+		}
+
+		if !sv.equal(orig, clone) {
+			return &ErrSVCloneOrigNotEqual{newErrSV("clone of the ORIGINAL with the only populated field %q" +
+				" is not the same as the original: orig - %s, clone - %s%s", field, sv.dump(orig), sv.dump(clone),
+				sv.diffNote(orig, clone, "orig", "clone"))}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+CheckEmptyCapacity enables an additional verification phase for slice fields.
+The Changer functions change the elements of slices, so a clone that copies
+the slice header of an empty (but non-nil) slice cannot be revealed: there are
+no elements to change, but appending to such slice of the clone writes to the
+capacity shared with the original.
+
+In this phase, each exported slice field of the original is set to a slice of
+zero length but nonzero capacity, then an element is appended to the field of
+the clone. If the appended element appears in the capacity region of the
+original slice, *[ErrSVOrigChanged] is returned. The appended elements are
+produced by the Setter functions, fields with elements that cannot be produced
+are not checked.
+*/
+func (sv *StructVerifier) CheckEmptyCapacity() *StructVerifier {
+	sv.emptyCap = true
+	return sv
+}
+
+// verifyEmptyCapacity sets the slice fields from the fields list to empty slices
+// with capacity, appends to the clone and checks the capacity of the original
+func (sv *StructVerifier) verifyEmptyCapacity(fields []string) error {
+	orig, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	// Slice fields of the original to check, fields expected to be shared are not checked
+	var slices []string
+	for _, field := range fields {
+		if sv.isShared(field) {
+			continue
+		}
+		if f := fieldByPath(reflect.ValueOf(orig).Elem(), field); f.Kind() == reflect.Slice {
+			f.Set(reflect.MakeSlice(f.Type(), 0, genericLen))
+			slices = append(slices, field)
+		}
+	}
+
+	if slices == nil {
+		// Nothing to check
+		return nil
+	}
+
+	clone, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	fl := sv.newFiller()
+	for _, field := range slices {
+		cf := fieldByPath(reflect.ValueOf(clone).Elem(), field)
+		val, err := fl.value(reflect.New(cf.Type().Elem()).Elem(), field + "[0]")
+		if err != nil || val.IsZero() {
+			// Cannot produce the element distinguishable from the zero value, skip the field
+			continue
+		}
+		cf.Set(reflect.Append(cf, val))
+
+		of := fieldByPath(reflect.ValueOf(orig).Elem(), field)
+		if capped := of.Slice(0, of.Cap()); sv.equal(capped.Index(0).Interface(), val.Interface()) {
+			return &ErrSVOrigChanged{newErrSV("element %s appended to the empty CLONE field %q has APPEARED" +
+				" in the capacity of the ORIGINAL slice", sv.dump(val.Interface()), field)}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// Number of clones compared by the deterministic clone phase, a single extra
+// call may produce the same result by chance, e.g. the same map iteration order
+const deterministicCalls = 16
+
+/*
+CheckDeterministic enables an additional verification phase for cloners that
+must produce the same clones of the same original. Some cloners accidentally
+introduce nondeterminism, e.g. build slices of the clone by iterating over maps,
+so the order of elements differs from call to call. Such clones can even pass
+the per-field verification if the comparison ignores the order (see
+[StructVerifier.RegisterComparator]), but break equality checks of the code
+using them.
+
+In this phase, the cloner is called several times for the same filled original
+and the produced clones must be deeply equal, as by [reflect.DeepEqual],
+regardless of the comparison options. Otherwise, the
+*[ErrSVCloneNondeterministic] error is returned. The phase is not enabled by
+default, since some clones legitimately differ, e.g. include timestamps.
+*/
+func (sv *StructVerifier) CheckDeterministic() *StructVerifier {
+	sv.deterministic = true
+	return sv
+}
+
+// verifyDeterministic calls the cloner for the same original several times
+// and checks that the produced clones are the same
+func (sv *StructVerifier) verifyDeterministic(orig any) error {
+	first, err := sv.callCloner(orig, "")
+	if err != nil {
+		return err
+	}
+
+	// Clones are compared strictly, without user-defined comparison settings
+	strict := NewStructVerifier(sv.creator, sv.cloner)
+	strict.maxDepth = sv.maxDepth
+
+	for i := 1; i < deterministicCalls; i++ {
+		clone, err := sv.callCloner(orig, "")
+		if err != nil {
+			return err
+		}
+
+		if !strict.equal(first, clone) {
+			return &ErrSVCloneNondeterministic{newErrSV("clones of the same ORIGINAL produced by different" +
+				" calls of the cloner DIFFER at %q: first - %s, call #%d - %s",
+				strict.diffPath(reflect.ValueOf(first).Elem(), reflect.ValueOf(clone).Elem(), "", 0),
+				sv.dump(first), i + 1, sv.dump(clone))}
+		}
+	}
+
+	// OK
+	return nil
+}
+
+/*
+CheckNilPointers enables an additional verification phase for pointer fields.
+The Changer functions change the values pointed by pointers, so the clone is
+verified by mutation through the pointer. But a clone may share an indirection
+level with the original, e.g. the pointer of the **T field, that is revealed
+only when the pointer stored at this level is replaced.
+
+In this phase, the pointers stored at each level of indirection of exported
+pointer fields of the clone are set to nil one at a time: the pointer the field
+points to, and so on while the pointed values are non-nil pointers. After each
+assignment, the original must remain the same as the reference, otherwise
+*[ErrSVOrigChanged] is returned. Then the field is set to nil in the filled
+original and the reference, the field of the clone must be nil too, otherwise
+*[ErrSVCloneOrigNotEqual] is returned, and setting it to a new value must not
+change the original. The fields of structures embedded by value are checked
+too, fields expected to be shared, see [StructVerifier.ExpectShared], are not.
+
+Pointers shared with the original are usually reported by the regular phases
+as *[ErrSVSharedPointer], but only up to the maximum depth (see
+[WithMaxDepth]), this phase does not depend on the depth and on the Changer
+functions.
+*/
+func (sv *StructVerifier) CheckNilPointers() *StructVerifier {
+	sv.nilPointers = true
+	return sv
+}
+
+// verifyNilPointers sets pointers of the pointer fields from the fields list
+// of clones to nil and checks that the original is not changed, then checks
+// clones of originals with nil pointer fields
+func (sv *StructVerifier) verifyNilPointers(orig, ref any, fields []string) error {
+	for _, field := range fields {
+		if sv.isShared(field) || fieldByPath(reflect.ValueOf(orig).Elem(), field).Kind() != reflect.Pointer {
+			continue
+		}
+
+		// The field itself belongs to the clone, start from the pointer it points to
+		for level := 1; ; level++ {
+			clone, err := sv.callCloner(orig, field)
+			if err != nil {
+				return err
+			}
+
+			// Go to the pointer of the required indirection level
+			p := fieldByPath(reflect.ValueOf(clone).Elem(), field)
+			for i := 0; i < level && p.Kind() == reflect.Pointer && !p.IsNil(); i++ {
+				p = p.Elem()
+			}
+			if p.Kind() != reflect.Pointer || p.IsNil() {
+				// No more pointers to set
+				break
+			}
+
+			p.Set(reflect.Zero(p.Type()))
+
+			if !sv.equal(orig, ref) {
+				return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+					" at %q after the CLONE pointer %q has been SET to nil, clone: %s%s",
+					sv.dump(orig), sv.dump(ref), sv.origDiff(orig, ref), strings.Repeat("*", level) + field,
+					sv.dump(clone), sv.diffNote(ref, orig, "ref", "orig"))}
+			}
+		}
+
+		if err := sv.verifyNilPointerField(field); err != nil {
+			return err
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// verifyNilPointerField checks the clone of the filled original with the nil
+// pointer field: the field of the clone must be nil and must be set to a new
+// value without changing the original
+func (sv *StructVerifier) verifyNilPointerField(field string) error {
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		return err
+	}
+	for _, x := range []any{orig, ref} {
+		f := fieldByPath(reflect.ValueOf(x).Elem(), field)
+		f.Set(reflect.Zero(f.Type()))
+	}
+
+	clone, err := sv.callCloner(orig, field)
+	if err != nil {
+		return err
+	}
+
+	cf := fieldByPath(reflect.ValueOf(clone).Elem(), field)
+	if !cf.IsNil() {
+		return &ErrSVCloneOrigNotEqual{newErrSV("CLONE pointer field %q must be nil as in the ORIGINAL," +
+			" but has value %s", field, sv.dump(cf.Interface()))}
+	}
+
+	v, err := sv.newFiller().value(cf, field)
+	if err != nil {
+		return &ErrSVChange{newErrSV("cannot set nil pointer field %q in the CLONE: %w", field, err)}
+	}
+	cf.Set(v)
+
+	if !sv.equal(orig, ref) {
+		return &ErrSVOrigChanged{newErrSV("the ORIGINAL value (%s) is DIFFERENT from the REFERENCE (%s)" +
+			" at %q after the nil CLONE pointer %q has been SET, clone: %s%s",
+			sv.dump(orig), sv.dump(ref), sv.origDiff(orig, ref), field,
+			sv.dump(clone), sv.diffNote(ref, orig, "ref", "orig"))}
+	}
+
+	// OK
+	return nil
+}
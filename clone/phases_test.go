@@ -0,0 +1,646 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestChangeAllFields(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any {
+			if c, ok := x.(*testComplexStruct); ok {
+				return c.Clone()
+			}
+			panic(fmt.Sprintf("unsupported type: got - %T, want - *testComplexStruct", x))
+		},
+	).AddSetters(intSliceSetter).AddChangers(intSliceChanger).ChangeAllFields()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with all fields changed failed: %v", err)
+	}
+}
+
+type testMirrored struct {
+	Left	[]int
+	Right	[]int
+}
+
+func TestChangeAllFieldsAliased(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testMirrored{} },
+		// Both fields of the clone refer to one copy, it is correct until
+		// the fields of the clone are changed together
+		func(x any) any {
+			orig := x.(*testMirrored)	//nolint:forcetypeassert
+			items := append([]int(nil), orig.Left...)
+			return &testMirrored{Left: items, Right: items}
+		},
+	).AddSettersWithField(func() SetterWithField {
+		// Fields are mirrored, so the clone can share one copy between them
+		return func(_ string, v reflect.Value) any {
+			if v.Type() != reflect.TypeOf([]int(nil)) {
+				return nil
+			}
+			return []int{1, 2}
+		}
+	}).AddChangers(func(v reflect.Value) bool {
+		// Changes are undone by the second change of the same value
+		if items, ok := v.Interface().([]int); ok && len(items) != 0 {
+			items[len(items) - 1] = -items[len(items) - 1]
+			return true
+		}
+		return false
+	})
+
+	// Each field of the clone is independent of the original
+	if err := sv.Verify(); err != nil {
+		t.Fatalf("verification of fields one by one failed: %v", err)
+	}
+
+	// The change of the second field undoes the change of the first one
+	err := sv.ChangeAllFields().Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because fields of the clone share one slice")
+	case errors.As(err, new(*ErrSVCloneOrigEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigEqual", err, err)
+	}
+}
+
+func Test_verifyAllFieldsFail(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		// Shallow copy, slices are shared
+		func(x any) any { rv := *x.(*testComplexStruct); return &rv },	//nolint:forcetypeassert
+	).AddSetters(intSliceSetter).AddChangers(intSliceChanger)
+
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		t.Fatalf("cannot fill original and reference structures: %v", err)
+	}
+
+	err = sv.verifyAllFields(orig, ref, structFields(orig))
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares slices with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestCheckNilInterfaces(t *testing.T) {
+	if err := newIfaceVerifier(copyAny).CheckNilInterfaces().Verify(); err != nil {
+		t.Errorf("verification with nil interface fields failed: %v", err)
+	}
+
+	// Cloner replaces nil interface values by the default value
+	err := newIfaceVerifier(func(x any) any {
+		if x == nil {
+			return 0
+		}
+		return copyAny(x)
+	}).CheckNilInterfaces().Verify()
+
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone does not preserve nil interface")
+	case errors.As(err, new(*ErrSVNilIfaceNotPreserved)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNilIfaceNotPreserved", err, err)
+	}
+}
+
+type testIfaceHolder struct {
+	Value	any
+}
+
+type testIfacePromoted struct {
+	testIfaceHolder
+	Name	string
+}
+
+// cloneIfacePromoted returns a clone of testIfacePromoted, the value of the
+// embedded structure is copied by the copyVal function
+func cloneIfacePromoted(x any, copyVal func(any) any) any {
+	rv := *x.(*testIfacePromoted)	//nolint:forcetypeassert
+	rv.Value = copyVal(rv.Value)
+	return &rv
+}
+
+func TestCheckNilInterfacesPromoted(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testIfacePromoted{} },
+		func(x any) any { return cloneIfacePromoted(x, copyAny) },
+	).RegisterConcrete(reflect.TypeOf((*any)(nil)).Elem(), func(n int) any { return []int{n} }).
+		CheckNilInterfaces()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with nil promoted interface fields failed: %v", err)
+	}
+
+	// Cloner replaces nil interface values of the embedded structure by the default value
+	sv = NewStructVerifier(
+		func() any { return &testIfacePromoted{} },
+		func(x any) any {
+			return cloneIfacePromoted(x, func(v any) any {
+				if v == nil {
+					return 0
+				}
+				return copyAny(v)
+			})
+		},
+	).RegisterConcrete(reflect.TypeOf((*any)(nil)).Elem(), func(n int) any { return []int{n} }).
+		CheckNilInterfaces()
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone does not preserve nil promoted interface")
+	case errors.As(err, new(*ErrSVNilIfaceNotPreserved)):
+		if !strings.Contains(err.Error(), `"testIfaceHolder.Value"`) {
+			t.Errorf("error does not contain the path of the promoted field: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNilIfaceNotPreserved", err, err)
+	}
+}
+
+type testOptions struct {
+	Names	[]string
+}
+
+// Clone returns a clone of o, it handles the nil receiver
+func (o *testOptions) Clone() *testOptions {
+	if o == nil {
+		return nil
+	}
+	return &testOptions{Names: append([]string(nil), o.Names...)}
+}
+
+func TestCheckNilReceiver(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testOptions{} },
+		func(x any) any { return x.(*testOptions).Clone() },	//nolint:forcetypeassert
+	).CheckNilReceiver()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the method cloner with nil receiver failed: %v", err)
+	}
+
+	// Returns untyped nil
+	sv = NewStructVerifier(
+		func() any { return &testOptions{} },
+		func(x any) any {
+			if x.(*testOptions) == nil {	//nolint:forcetypeassert
+				return nil
+			}
+			return x.(*testOptions).Clone()	//nolint:forcetypeassert
+		},
+	).CheckNilReceiver()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the untyped nil cloner with nil receiver failed: %v", err)
+	}
+}
+
+func TestCheckNilReceiverNotNil(t *testing.T) {
+	// Returns empty structure instead of nil
+	sv := NewStructVerifier(
+		func() any { return &testOptions{} },
+		func(x any) any {
+			if x.(*testOptions) == nil {	//nolint:forcetypeassert
+				return &testOptions{}
+			}
+			return x.(*testOptions).Clone()	//nolint:forcetypeassert
+		},
+	).CheckNilReceiver()
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because cloner returns empty structure for nil")
+	case errors.As(err, new(*ErrSVNilReceiverNotNil)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNilReceiverNotNil", err, err)
+	}
+}
+
+func TestCheckNilReceiverPanic(t *testing.T) {
+	// Dereferences the nil pointer
+	sv := NewStructVerifier(
+		func() any { return &testOptions{} },
+		func(x any) any {
+			rv := *x.(*testOptions)	//nolint:forcetypeassert
+			rv.Names = append([]string(nil), rv.Names...)
+			return &rv
+		},
+	).CheckNilReceiver()
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because cloner dereferences nil pointer")
+	case errors.As(err, new(*ErrSVClonePanic)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVClonePanic", err, err)
+	}
+}
+
+func TestInsertMapKeys(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+	).AddSetters(intSliceSetter).AddChangers(intSliceChanger).InsertMapKeys()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with key insertion failed: %v", err)
+	}
+}
+
+func TestInsertMapKeysShared(t *testing.T) {
+	// The map is shared with the original
+	sv := NewStructVerifier(
+		func() any { return newTestComplexStruct() },
+		func(x any) any {
+			rv := x.(*testComplexStruct).Clone()	//nolint:forcetypeassert
+			rv.MapVals = x.(*testComplexStruct).MapVals	//nolint:forcetypeassert
+			return rv
+		},
+	).AddSetters(intSliceSetter).AddChangers(intSliceChanger).InsertMapKeys()
+
+	err := sv.verifyInsertKeys()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the map")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testMapHolder struct {
+	Index	map[string]int
+}
+
+type testMapPromoted struct {
+	testMapHolder
+	Name	string
+}
+
+func TestInsertMapKeysPromoted(t *testing.T) {
+	// The map of the embedded structure is shared with the original
+	sv := NewStructVerifier(
+		func() any { return &testMapPromoted{} },
+		func(x any) any {
+			rv := *x.(*testMapPromoted)	//nolint:forcetypeassert
+			return &rv
+		},
+	).InsertMapKeys()
+
+	err := sv.verifyInsertKeys()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the map of the embedded structure")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		if !strings.Contains(err.Error(), `"testMapHolder.Index"`) {
+			t.Errorf("error does not contain the path of the promoted field: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testSparse struct {
+	Vals	[]int
+	First	int
+	Tags	map[string]string
+}
+
+// cloneSparse returns a clone of testSparse, if mustFirst is set the cloner
+// panics on the empty slice, if nilToEmpty is set nil map is cloned as empty
+func cloneSparse(x any, mustFirst, nilToEmpty bool) any {
+	orig := x.(*testSparse)	//nolint:forcetypeassert
+	rv := &testSparse{First: orig.First, Vals: append([]int(nil), orig.Vals...)}
+	if mustFirst {
+		// Wrongly assume the slice is never empty
+		_ = orig.Vals[0]
+	}
+	if orig.Tags != nil || nilToEmpty {
+		rv.Tags = make(map[string]string, len(orig.Tags))
+		for k, v := range orig.Tags {
+			rv.Tags[k] = v
+		}
+	}
+	return rv
+}
+
+func TestCheckSparseFields(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testSparse{} },
+		func(x any) any { return cloneSparse(x, false, false) },
+		WithCheckSparseFields(),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with sparse structures failed: %v", err)
+	}
+}
+
+func TestCheckSparseFieldsPanic(t *testing.T) {
+	// Panic on the empty slice
+	sv := NewStructVerifierWith(
+		func() any { return &testSparse{} },
+		func(x any) any { return cloneSparse(x, true, false) },
+		WithCheckSparseFields(),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because cloner panics on the empty slice")
+	case errors.As(err, new(*ErrSVClonePanic)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVClonePanic", err, err)
+	}
+}
+
+func TestCheckSparseFieldsNilToEmpty(t *testing.T) {
+	// Nil map is replaced by the empty one
+	sv := NewStructVerifierWith(
+		func() any { return &testSparse{} },
+		func(x any) any { return cloneSparse(x, false, true) },
+		WithCheckSparseFields(),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because cloner replaces nil map by the empty one")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+}
+
+type testCaps struct {
+	Vals	[]int
+	Names	[]string
+}
+
+func TestCheckEmptyCapacity(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testCaps{} },
+		func(x any) any {
+			orig := x.(*testCaps)	//nolint:forcetypeassert
+			return &testCaps{
+				Vals:	append(make([]int, 0, len(orig.Vals)), orig.Vals...),
+				Names:	append(make([]string, 0, len(orig.Names)), orig.Names...),
+			}
+		},
+		WithCheckEmptyCapacity(),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with empty slices failed: %v", err)
+	}
+}
+
+func TestCheckEmptyCapacityHeaders(t *testing.T) {
+	// Empty slices are copied by headers
+	sv := NewStructVerifierWith(
+		func() any { return &testCaps{} },
+		func(x any) any {
+			orig := x.(*testCaps)	//nolint:forcetypeassert
+			rv := *orig
+			if len(orig.Names) != 0 {
+				rv.Names = append([]string(nil), orig.Names...)
+			}
+			if len(orig.Vals) != 0 {
+				rv.Vals = append([]int(nil), orig.Vals...)
+			}
+			return &rv
+		},
+		WithCheckEmptyCapacity(),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares capacity of empty slices")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+func TestCheckEmptyCapacityShared(t *testing.T) {
+	type testPool struct {
+		Vals	[]int
+		Free	[]int	// shared with the clone by design
+	}
+
+	sv := NewStructVerifierWith(
+		func() any { return &testPool{} },
+		func(x any) any {
+			orig := x.(*testPool)	//nolint:forcetypeassert
+			return &testPool{Vals: append(make([]int, 0, len(orig.Vals)), orig.Vals...), Free: orig.Free}
+		},
+		WithCheckEmptyCapacity(), WithExpectShared("Free"),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with empty slice expected to be shared failed: %v", err)
+	}
+}
+
+type testBag struct {
+	Names	[]string
+	Size	int
+}
+
+// cloneBag returns a clone of testBag, the order of names of the clone
+// depends on the map iteration order if shuffle is set
+func cloneBag(x any, shuffle bool) any {
+	orig := x.(*testBag)	//nolint:forcetypeassert
+	rv := &testBag{Size: orig.Size}
+	if !shuffle {
+		rv.Names = append([]string(nil), orig.Names...)
+		return rv
+	}
+
+	idx := make(map[int]bool, len(orig.Names))
+	for i := range orig.Names {
+		idx[i] = true
+	}
+	for i := range idx {
+		rv.Names = append(rv.Names, orig.Names[i])
+	}
+
+	return rv
+}
+
+// sameNames compares names regardless of their order
+func sameNames(a, b []string) bool {
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+// distinctNamesSetter fills []string fields with distinct names to get different orders
+func distinctNamesSetter() Setter {
+	n := 0
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().([]string); !ok {
+			return nil
+		}
+		n++
+		names := make([]string, 10)
+		for i := range names {
+			names[i] = fmt.Sprint(n, "_", i)
+		}
+		return names
+	}
+}
+
+func TestCheckDeterministic(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testBag{} },
+		func(x any) any { return cloneBag(x, false) },
+		WithComparator(sameNames),
+		WithCheckDeterministic(),
+		WithSetters(distinctNamesSetter),
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the deterministic clone failed: %v", err)
+	}
+}
+
+func TestCheckDeterministicShuffle(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return &testBag{} },
+		func(x any) any { return cloneBag(x, true) },
+		WithComparator(sameNames),
+		WithCheckDeterministic(),
+		WithSetters(distinctNamesSetter),
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because order of names depends on the map iteration order")
+	case errors.As(err, new(*ErrSVCloneNondeterministic)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneNondeterministic", err, err)
+	}
+}
+
+type testOverride struct {
+	Name	string
+	Value	**string
+}
+
+// cloneOverride returns a clone of testOverride, the outer pointer of the
+// value is shared with the original if shareOuter is set
+func cloneOverride(x any, shareOuter bool) any {
+	orig := x.(*testOverride)	//nolint:forcetypeassert
+	rv := &testOverride{Name: orig.Name, Value: orig.Value}
+	if !shareOuter && orig.Value != nil {
+		inner := new(string)
+		*inner = **orig.Value
+		rv.Value = &inner
+	}
+
+	return rv
+}
+
+func TestCheckNilPointers(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testOverride{} },
+		func(x any) any { return cloneOverride(x, false) },
+	).CheckNilPointers()
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of the deep clone failed: %v", err)
+	}
+}
+
+func Test_verifyNilPointers(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testOverride{} },
+		func(x any) any { return cloneOverride(x, true) },
+	).CheckNilPointers()
+
+	// Check the phase separately, the shared pointer is also revealed by the regular phase
+	orig, ref, err := sv.fillOrigRef()
+	if err != nil {
+		t.Fatalf("cannot fill original and reference structures: %v", err)
+	}
+
+	err = sv.verifyNilPointers(orig, ref, sv.verifiedFields())
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the outer pointer")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		if !strings.Contains(err.Error(), `"*Value"`) {
+			t.Errorf("error does not contain the pointer path: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
+
+type testLimitHolder struct {
+	Limit	*int
+}
+
+type testLimitPromoted struct {
+	testLimitHolder
+	Name	string
+}
+
+func TestCheckNilPointersNotPreserved(t *testing.T) {
+	// Cloner replaces the nil limit of the embedded structure by the default one
+	sv := NewStructVerifier(
+		func() any { return &testLimitPromoted{} },
+		func(x any) any {
+			orig := x.(*testLimitPromoted)	//nolint:forcetypeassert
+			rv := &testLimitPromoted{Name: orig.Name}
+			rv.Limit = new(int)
+			if orig.Limit != nil {
+				*rv.Limit = *orig.Limit
+			}
+			return rv
+		},
+	).CheckNilPointers()
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone does not preserve nil pointer")
+	case errors.As(err, new(*ErrSVCloneOrigNotEqual)):
+		if !strings.Contains(err.Error(), `"testLimitHolder.Limit"`) {
+			t.Errorf("error does not contain the path of the promoted field: %v", err)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+}
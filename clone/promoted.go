@@ -0,0 +1,98 @@
+package clone
+
+import (
+	"reflect"
+	"strings"
+)
+
+// isValueEmbedded reports whether the field f is a structure embedded by value,
+// such fields are verified by their promoted fields, e.g. "B.Items"
+func isValueEmbedded(f reflect.StructField) bool {
+	if !f.Anonymous || f.Type.Kind() != reflect.Struct || isAtomic(f.Type) {
+		return false
+	}
+
+	for i := 0; i < f.Type.NumField(); i++ {
+		if verifiable(f.Type.Field(i)) {
+			return true
+		}
+	}
+
+	// Nothing to promote
+	return false
+}
+
+// expandField returns the paths of the promoted fields of the field f if it is
+// a structure embedded by value, otherwise the path itself is returned
+func expandField(f reflect.StructField, path string) []string {
+	if !isValueEmbedded(f) {
+		return []string{path}
+	}
+
+	var paths []string
+	for i := 0; i < f.Type.NumField(); i++ {
+		if pf := f.Type.Field(i); verifiable(pf) || isValueEmbedded(pf) {
+			paths = append(paths, expandField(pf, path + "." + pf.Name)...)
+		}
+	}
+
+	return paths
+}
+
+// fieldByPath returns the field of the structure s by its path, like "B.Items".
+// It returns the invalid value if there is no such field
+func fieldByPath(s reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		if s.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+		if s = s.FieldByName(name); !s.IsValid() {
+			return s
+		}
+	}
+
+	return s
+}
+
+// typeFieldByPath returns the field of the structure type t by its path, like "B.Items"
+func typeFieldByPath(t reflect.Type, path string) (reflect.StructField, bool) {
+	var f reflect.StructField
+	for _, name := range strings.Split(path, ".") {
+		if t.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+
+		var ok bool
+		if f, ok = t.FieldByName(name); !ok {
+			return reflect.StructField{}, false
+		}
+		t = f.Type
+	}
+
+	return f, true
+}
+
+// fillPromoted fills the promoted fields of the structure f embedded by value
+// into the structure s one by one, it is used for unexported embedded structures
+// which cannot be set as a whole
+func (sv *StructVerifier) fillPromoted(fl *filler, s reflect.Value, f reflect.StructField) error {
+	for _, path := range expandField(f, f.Name) {
+		if sv.skipped[path] {
+			continue
+		}
+
+		pf := fieldByPath(s, path)
+		v, err := fl.value(pf, path)
+		if err != nil {
+			if sv.partial {
+				// Leave the field with its initial value
+				sv.skipField(path, err)
+				continue
+			}
+			return err
+		}
+		pf.Set(v)
+	}
+
+	return nil
+}
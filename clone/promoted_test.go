@@ -0,0 +1,60 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testEmbeddedInner struct {
+	Items	[]int
+	Name	string
+}
+
+type testEmbeddedOuter struct {
+	testEmbeddedInner
+	Count	int
+}
+
+// cloneEmbeddedOuter returns a clone of testEmbeddedOuter, if shareItems
+// is true, the items of the embedded structure are shared with the original
+func cloneEmbeddedOuter(x any, shareItems bool) any {
+	rv := *x.(*testEmbeddedOuter)	//nolint:forcetypeassert
+	if !shareItems {
+		rv.Items = append([]int(nil), rv.Items...)
+	}
+
+	return &rv
+}
+
+func TestCloneValueEmbedded(t *testing.T) {
+	var fields []string
+	if err := NewStructVerifier(
+		func() any { return &testEmbeddedOuter{} },
+		func(x any) any { return cloneEmbeddedOuter(x, false) },
+	).OnFieldStart(func(field string) { fields = append(fields, field) }).Verify(); err != nil {
+		t.Errorf("verification of structure with embedded structure failed: %v", err)
+	}
+
+	want := []string{"testEmbeddedInner.Items", "testEmbeddedInner.Name", "Count"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got verified fields %q, want - %q", fields, want)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &testEmbeddedOuter{} },
+		func(x any) any { return cloneEmbeddedOuter(x, true) },
+	).Verify()
+	var errChanged *ErrSVOrigChanged
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares items of the embedded structure")
+	case errors.As(err, &errChanged):
+		if want := `"testEmbeddedInner.Items"`; !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report the field %s", err, want)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
@@ -0,0 +1,42 @@
+package clone
+
+import (
+	"reflect"
+)
+
+/*
+ReversingChanger is an optional [Changer] that reverses the order of slice
+elements in place. If the clone shares the backing array with the original,
+the original becomes reordered too, e.g. as if the clone has been sorted in
+place. It catches sharing that the modification of a single element misses,
+when that element happens to be copied. Use it explicitly:
+
+  sv.AddChangers(clone.ReversingChanger)
+
+It handles slices of any types, including named types. Slices with less than
+two elements and palindromic slices are not changed by reversing, so they are
+passed to the next Changer functions.
+*/
+func ReversingChanger(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice || v.Len() < 2 || !v.CanInterface() || palindrome(v) {
+		return false
+	}
+
+	swap := reflect.Swapper(v.Interface())
+	for i, j := 0, v.Len() - 1; i < j; i, j = i + 1, j - 1 {
+		swap(i, j)
+	}
+
+	return true
+}
+
+// palindrome returns true if the slice v is the same when reversed
+func palindrome(v reflect.Value) bool {
+	for i, j := 0, v.Len() - 1; i < j; i, j = i + 1, j - 1 {
+		if !reflect.DeepEqual(v.Index(i).Interface(), v.Index(j).Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,46 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReversingChanger(t *testing.T) {
+	type listStruct struct {
+		Items	[]string
+	}
+
+	cloneList := func(x any) any {
+		orig := x.(*listStruct)	//nolint:forcetypeassert
+		rv := *orig
+		rv.Items = append([]string(nil), orig.Items...)
+		return &rv
+	}
+
+	// cloneShared returns a clone that shares the backing array of items
+	cloneShared := func(x any) any {
+		rv := *x.(*listStruct)	//nolint:forcetypeassert
+		return &rv
+	}
+
+	if err := NewStructVerifier(func() any { return &listStruct{} }, cloneList).
+		AddChangers(ReversingChanger).Verify(); err != nil {
+		t.Errorf("verification with reversing changer failed: %v", err)
+	}
+
+	err := NewStructVerifier(func() any { return &listStruct{} }, cloneShared).
+		AddChangers(ReversingChanger).Verify()
+	if !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	s := []int{1, 2, 3}
+	if !ReversingChanger(reflect.ValueOf(s)) || !reflect.DeepEqual(s, []int{3, 2, 1}) {
+		t.Errorf("reversing changer did not reverse the slice: %v", s)
+	}
+
+	if ReversingChanger(reflect.ValueOf([]int{1, 2, 1})) || ReversingChanger(reflect.ValueOf([]int{1})) {
+		t.Errorf("reversing changer handled the slice that cannot be changed by reversing")
+	}
+}
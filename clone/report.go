@@ -0,0 +1,138 @@
+package clone
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Statuses of fields in the verification report, see [FieldReport]
+const (
+	FieldPassed		=	"passed"	// the field has been verified successfully
+	FieldFailed		=	"failed"	// the verification of the field failed
+	FieldSkipped	=	"skipped"	// the field has been skipped in the partial mode
+	FieldNotRun		=	"not run"	// the verification stopped before the field
+)
+
+// FieldReport contains the result of the verification of one field, see [Report].
+// Handler is the name of the handler that fills the values of the field, like
+// "setter" (user-defined setters), "field setter" (setters taking field names),
+// "concrete", "external", "embedded", "named", "atomic", "sync.Map", "func" or
+// "generic" (pointers, structures, slices, arrays and maps filled element by
+// element). It is empty if the field cannot be filled.
+type FieldReport struct {
+	Field		string	`json:"field"`
+	Type		string	`json:"type"`
+	Handler		string	`json:"handler,omitempty"`
+	Status		string	`json:"status"`
+	ErrorType	string	`json:"error_type,omitempty"`
+	Error		string	`json:"error,omitempty"`
+}
+
+// Report contains the structured result of the verification, see
+// [StructVerifier.VerifyReport]. Error types are represented by their names,
+// e.g. "ErrSVOrigChanged".
+type Report struct {
+	Type		string			`json:"type"`
+	Passed		bool			`json:"passed"`
+	ErrorType	string			`json:"error_type,omitempty"`
+	Error		string			`json:"error,omitempty"`
+	Warnings	[]string		`json:"warnings,omitempty"`
+	Fields		[]FieldReport	`json:"fields"`
+}
+
+/*
+VerifyReport works like [StructVerifier.Verify], but also returns the report
+with the status of each field, it can be serialized to JSON by [Report.JSON]
+to be processed by CI tools, e.g. to track which fields and types are covered
+by the verification over time. The report is returned even if the verification
+fails, the fields not reached by the verification have the [FieldNotRun] status.
+
+The hook set by [StructVerifier.OnFieldDone] is called as usual.
+*/
+func (sv *StructVerifier) VerifyReport() (*Report, error) {
+	var mu sync.Mutex
+	results := map[string]error{}
+
+	hook := sv.onFieldDone
+	defer func() { sv.onFieldDone = hook }()
+	sv.onFieldDone = func(field string, err error) {
+		mu.Lock()
+		results[field] = err
+		mu.Unlock()
+
+		if hook != nil {
+			hook(field, err)
+		}
+	}
+
+	err := sv.Verify()
+
+	t := reflect.TypeOf(sv.creator()).Elem()
+	report := &Report{
+		Type:		t.String(),
+		Passed:		err == nil,
+		ErrorType:	errorTypeName(err),
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	for _, w := range sv.warnings {
+		report.Warnings = append(report.Warnings, w.Error())
+	}
+
+	// Skipped fields are excluded from the verified fields
+	fields := sv.verifiedFields()
+	fields = append(fields, sv.SkippedFields()...)
+
+	// Separate filler to find handlers of fields without affecting the verifier
+	fl := sv.newFiller()
+	for _, field := range fields {
+		fr := FieldReport{Field: field, Status: FieldNotRun}
+		if f, ok := typeFieldByPath(t, field); ok {
+			fr.Type = f.Type.String()
+			if _, handler, err := fl.fill(reflect.New(f.Type).Elem(), field); err == nil {
+				fr.Handler = handler
+			}
+		}
+
+		switch fErr, ok := results[field]; {
+		case sv.skipped[field]:
+			fr.Status = FieldSkipped
+		case !ok:
+			// Not reached by the verification
+		case fErr == nil:
+			fr.Status = FieldPassed
+		default:
+			fr.Status, fr.ErrorType, fr.Error = FieldFailed, errorTypeName(fErr), fErr.Error()
+		}
+
+		report.Fields = append(report.Fields, fr)
+	}
+
+	return report, err
+}
+
+// JSON returns the report serialized to JSON.
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// errorTypeName returns the name of the type of err without the package
+// name and the pointer mark, e.g. "ErrSVOrigChanged", or an empty string if
+// err is nil
+func errorTypeName(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	t := reflect.TypeOf(err)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+
+	return t.Name()
+}
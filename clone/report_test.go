@@ -0,0 +1,107 @@
+package clone
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testReported struct {
+	Name	string
+	Tags	[]string
+	Count	int
+}
+
+func TestVerifyReport(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testReported{} },
+		func(x any) any {
+			rv := *x.(*testReported)	//nolint:forcetypeassert
+			rv.Tags = append([]string(nil), rv.Tags...)
+			return &rv
+		},
+	)
+
+	report, err := sv.VerifyReport()
+	if err != nil || !report.Passed {
+		t.Fatalf("verification failed: %v", err)
+	}
+	for _, fr := range report.Fields {
+		if fr.Status != FieldPassed {
+			t.Errorf("unexpected result of successfully verified field: %#v", fr)
+		}
+	}
+}
+
+func TestVerifyReportFailed(t *testing.T) {
+	// The user-defined hook must be called as usual
+	var done []string
+	sv := NewStructVerifier(
+		func() any { return &testReported{} },
+		func(x any) any {
+			rv := *x.(*testReported)	//nolint:forcetypeassert
+			return &rv
+		},
+	).OnFieldDone(func(field string, _ error) { done = append(done, field) })
+
+	report, err := sv.VerifyReport()
+	switch {
+	case err == nil:
+		t.Fatalf("returned no error but must fail, because clone shares the Tags slice with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+	if len(done) == 0 {
+		t.Errorf("OnFieldDone hook was not called")
+	}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatalf("cannot serialize report: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("cannot deserialize report: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(&decoded, report) {
+		t.Errorf("deserialized report %#v differs from the original %#v", decoded, report)
+	}
+
+	if decoded.Passed || decoded.Type != "clone.testReported" || decoded.ErrorType != "ErrSVOrigChanged" {
+		t.Errorf("unexpected report: %s", data)
+	}
+	want := map[string]string{"Name": FieldPassed, "Tags": FieldFailed, "Count": FieldNotRun}
+	for _, fr := range decoded.Fields {
+		if fr.Status != want[fr.Field] {
+			t.Errorf("field %q (%s) has status %q, want - %q", fr.Field, fr.Type, fr.Status, want[fr.Field])
+		}
+		if fr.Field == "Tags" && (fr.ErrorType != "ErrSVOrigChanged" || fr.Type != "[]string" || fr.Handler != "embedded") {
+			t.Errorf("unexpected report of the failed field: %#v", fr)
+		}
+	}
+}
+
+func TestVerifyReportPromoted(t *testing.T) {
+	report, err := NewStructVerifier(
+		func() any { return &testEmbeddedOuter{} },
+		func(x any) any { return cloneEmbeddedOuter(x, false) },
+	).VerifyReport()
+	if err != nil {
+		t.Fatalf("verification of structure with embedded structure failed: %v", err)
+	}
+
+	// Rows must be the same as the fields verified by Verify
+	var fields []string
+	for _, fr := range report.Fields {
+		fields = append(fields, fr.Field)
+		if fr.Status != FieldPassed {
+			t.Errorf("unexpected result of successfully verified field: %#v", fr)
+		}
+	}
+	if want := []string{"testEmbeddedInner.Items", "testEmbeddedInner.Name", "Count"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("got report fields %q, want - %q", fields, want)
+	}
+}
@@ -0,0 +1,83 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+CaptureRepro makes [StructVerifier.Verify] capture the values that caused the
+*[ErrSVCloneOrigNotEqual] or *[ErrSVOrigChanged] error of the field verification
+and format them as a compact Go snippet, available by the Repro method of the
+error:
+
+  var errChanged *clone.ErrSVOrigChanged
+  if errors.As(err, &errChanged) {
+      t.Log(errChanged.Repro())
+  }
+
+The snippet shows the original, the change applied to the field of the clone
+and the resulting divergence, e.g.:
+
+  orig := &pkg.Config{Name:"b_1", Vals:[]int{1, 2}}
+  c := cloner(orig)
+  c.Vals = []int{1, 4} // was []int{1, 2}
+  // orig.Vals is []int{1, 4}, want []int{1, 2} (differs at "Vals[1]")
+
+The values are rendered the same way as in error messages, see [StructVerifier.SetMaxErrorLen].
+*/
+func (sv *StructVerifier) CaptureRepro() *StructVerifier {
+	sv.repro = true
+	return sv
+}
+
+// Repro returns the Go snippet reproducing the failure if it has been captured,
+// see [StructVerifier.CaptureRepro], otherwise it returns an empty string.
+func (esv structVerifierError) Repro() string {
+	return esv.repro
+}
+
+// reproNotEqual returns the snippet reproducing the clone which is not equal to the original
+func (sv *StructVerifier) reproNotEqual(orig, clone any) string {
+	path := sv.diffPath(reflect.ValueOf(orig).Elem(), reflect.ValueOf(clone).Elem(), "", 0)
+	expr, got := sv.pathValue(clone, path)
+	_, want := sv.pathValue(orig, path)
+
+	return fmt.Sprintf("orig := %s\nc := cloner(orig)\n// c.%s is %s, want %s (differs at %q)\n",
+		sv.dump(orig), expr, got, want, path)
+}
+
+// reproChanged returns the snippet reproducing the original changed by the change of
+// the field of the clone, the ref holds the original value, before is the value of
+// the clone field before the change
+func (sv *StructVerifier) reproChanged(orig, ref, clone any, field, before string) string {
+	path := sv.origDiff(orig, ref)
+	expr, got := sv.pathValue(orig, path)
+	_, want := sv.pathValue(ref, path)
+
+	return fmt.Sprintf("orig := %s\nc := cloner(orig)\nc.%s = %s // was %s\n" +
+		"// orig.%s is %s, want %s (differs at %q)\n",
+		sv.dump(ref), field, sv.dump(fieldByPath(reflect.ValueOf(clone).Elem(), field)), before,
+		expr, got, want, path)
+}
+
+// pathValue returns the selector expression of the field of the structure si
+// on the path, like "B.Items" for the path "B.Items[1]", and its rendered value
+func (sv *StructVerifier) pathValue(si any, path string) (string, string) {
+	s := reflect.ValueOf(si).Elem()
+
+	// Try the selector without indexes, then the top-level field only
+	expr, _, _ := strings.Cut(path, "[")
+	if v := fieldByPath(s, expr); v.IsValid() {
+		return expr, sv.dump(v)
+	}
+
+	expr, _, _ = strings.Cut(expr, ".")
+	if v := fieldByPath(s, expr); v.IsValid() {
+		return expr, sv.dump(v)
+	}
+
+	// The difference cannot be localized
+	return "", sv.dump(si)
+}
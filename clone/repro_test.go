@@ -0,0 +1,60 @@
+package clone
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRepro struct {
+	Name	string
+	Vals	[]int
+}
+
+func TestCaptureReproShared(t *testing.T) {
+	// The clone shares values with the original
+	sv := NewStructVerifierWith(
+		func() any { return &testRepro{} },
+		func(x any) any { rv := *x.(*testRepro); return &rv },	//nolint:forcetypeassert
+		WithCaptureRepro(),
+	)
+
+	err := sv.Verify()
+
+	var errChanged *ErrSVOrigChanged
+	if !errors.As(err, &errChanged) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+	want := `orig := &clone.testRepro{Name:"b_1", Vals:[]int{1, 2}}
+c := cloner(orig)
+c.Vals = []int{1, 4} // was []int{1, 2}
+// orig.Vals is []int{1, 4}, want []int{1, 2} (differs at "Vals[1]")
+`
+	if got := errChanged.Repro(); got != want {
+		t.Errorf("got reproduction:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCaptureReproNotEqual(t *testing.T) {
+	// The clone loses the name
+	sv := NewStructVerifierWith(
+		func() any { return &testRepro{} },
+		func(x any) any {
+			return &testRepro{Vals: append([]int(nil), x.(*testRepro).Vals...)}	//nolint:forcetypeassert
+		},
+		WithCaptureRepro(),
+	)
+
+	err := sv.Verify()
+
+	var errNotEqual *ErrSVCloneOrigNotEqual
+	if !errors.As(err, &errNotEqual) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVCloneOrigNotEqual", err, err)
+	}
+	want := `orig := &clone.testRepro{Name:"b_1", Vals:[]int{1, 2}}
+c := cloner(orig)
+// c.Name is "", want "b_1" (differs at "Name")
+`
+	if got := errNotEqual.Repro(); got != want {
+		t.Errorf("got reproduction:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package clone
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+SeedState holds the current values of the counters used by the embedded Setter
+functions and by the producers of concrete values (see
+[StructVerifier.RegisterConcrete]) to generate values of fields. Each filling
+of a structure starts from the seed state of the [StructVerifier], so the
+original and the reference structures are filled by the same values.
+
+The state reached by the last filling can be taken by [StructVerifier.SeedState]
+and restored by [StructVerifier.RestoreSeedState], then the following fillings
+start from it and produce the values that follow the values of the last filled
+structure. The state taken before any filling is the initial one, restoring it
+reproduces the values of fields, e.g. in multi-phase tests. The zero SeedState
+is not valid, use the state taken from the verifier.
+*/
+type SeedState struct {
+	intVal	int
+	i64v	int64
+	floatVal	int
+	runeVal	rune
+	nStrs	int	// strings length grows with nStrs, so keep it small
+	bigVal	int64
+	strVal	int
+	durVal	time.Duration
+	ipVal	int
+	setVal	int
+	byteVal	int
+	seq		int	// sequence number of the last produced concrete value
+}
+
+// String returns the values of all counters of the state, e.g. to be logged
+// along with the verification failure to reproduce it.
+func (st SeedState) String() string {
+	return fmt.Sprintf("int=%d int64=%d float=%d rune=%d strings=%d big=%d string=%d" +
+		" duration=%d ip=%d set=%d byte=%d seq=%d",
+		st.intVal, st.i64v, st.floatVal, st.runeVal, st.nStrs, st.bigVal, st.strVal,
+		st.durVal, st.ipVal, st.setVal, st.byteVal, st.seq)
+}
+
+// newSeedState returns the state, initial values of which are shifted by the seed value
+func newSeedState(seed int) SeedState {
+	return SeedState{
+		intVal:		seed,
+		i64v:		int64(seed),
+		floatVal:	seed,
+		runeVal:	rune(seed),
+		nStrs:		initialSeed + seed % ('z' - 'a'),
+		bigVal:		int64(seed),
+		strVal:		seed,
+		durVal:		time.Duration(seed),
+		ipVal:		seed,
+		setVal:		seed,
+		byteVal:	seed,
+		seq:		seed,
+	}
+}
+
+// SeedState returns the snapshot of the seed state reached by the last filling
+// of a structure, or the initial seed state if nothing has been filled yet.
+func (sv *StructVerifier) SeedState() SeedState {
+	if sv.lastState != nil {
+		return *sv.lastState
+	}
+
+	return sv.state
+}
+
+// RestoreSeedState sets the seed state the following fillings of structures
+// start from, st must be the snapshot taken by [StructVerifier.SeedState]
+// earlier. It panics if st is the zero SeedState.
+func (sv *StructVerifier) RestoreSeedState(st SeedState) *StructVerifier {
+	if st == (SeedState{}) {
+		panic("RestoreSeedState: zero seed state, use the state taken by SeedState")
+	}
+
+	sv.state = st
+	sv.lastState = nil
+	return sv
+}
@@ -0,0 +1,81 @@
+package clone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeedStateRestore(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+		WithSetters(intSliceSetter), WithSeed(5),
+	)
+
+	fill := func() any {
+		x, err := sv.autoFill()
+		if err != nil {
+			t.Fatalf("cannot autofill: %v", err)
+		}
+		return x
+	}
+
+	snapshot := sv.SeedState()
+	first := fill()
+
+	sv.RestoreSeedState(newSeedState(9))
+	if other := fill(); reflect.DeepEqual(first, other) {
+		t.Errorf("structures filled with different seed states are equal: %#v", other)
+	}
+
+	sv.RestoreSeedState(snapshot)
+	if restored := fill(); !reflect.DeepEqual(first, restored) {
+		t.Errorf("structure filled with restored seed state %#v differs from the original %#v", restored, first)
+	}
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification with restored seed state failed: %v", err)
+	}
+}
+
+func TestSeedStateAfterFill(t *testing.T) {
+	sv := NewStructVerifierWith(
+		func() any { return newTestComplexStruct() },
+		func(x any) any { return x.(*testComplexStruct).Clone() },	//nolint:forcetypeassert
+		WithSetters(intSliceSetter),
+	)
+
+	initial := sv.SeedState()
+	first, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill: %v", err)
+	}
+
+	// The state reached by the filling differs from the initial one
+	reached := sv.SeedState()
+	if reached == initial {
+		t.Fatalf("seed state was not advanced by the filling: %#v", reached)
+	}
+
+	// Fillings continue from the restored state
+	sv.RestoreSeedState(reached)
+	next, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill: %v", err)
+	}
+	if reflect.DeepEqual(first, next) {
+		t.Errorf("structure filled from the reached state is equal to the first one: %#v", next)
+	}
+	sv.RestoreSeedState(reached)
+	if again, _ := sv.autoFill(); !reflect.DeepEqual(next, again) {
+		t.Errorf("structure filled from the same restored state %#v differs from %#v", again, next)
+	}
+
+	// The zero state must be rejected
+	defer func() {
+		if recover() == nil {
+			t.Errorf("zero seed state was restored without panic")
+		}
+	}()
+	sv.RestoreSeedState(SeedState{})
+}
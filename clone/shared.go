@@ -0,0 +1,137 @@
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// ErrSVNotShared represents an error that occurs when the field expected to be
+// shared between the clone and the original is not shared, see
+// [StructVerifier.ExpectShared]. Field contains the name of the field.
+type ErrSVNotShared struct {
+	structVerifierError
+	Field	string
+}
+
+// The struct tag key and value marking the field as intentionally shared
+const (
+	sharedTagKey	= "clone"
+	sharedTagValue	= "shared"
+)
+
+/*
+ExpectShared marks the fields as intentionally shared between the clone and the
+original, like interned strings, read-only lookup tables or singletons. The same
+can be done by the struct tag of the field:
+
+  type Config struct {
+      Name    string
+      Lookup  map[string]int `clone:"shared"`
+  }
+
+For such fields, the independence check is flipped: the verification fails with
+*[ErrSVNotShared] if the field of the clone does NOT refer to the same data as
+the field of the original. Shared fields are never changed by the verifier,
+because the change would be visible through the original.
+
+Only fields of pointers, maps, slices, channels, strings and interfaces holding
+them can be shared, any other type of the shared field is reported by
+*[ErrSVNotShared] too. Unknown field names are reported by *[ErrSVFieldNotFound].
+*/
+func (sv *StructVerifier) ExpectShared(fields ...string) *StructVerifier {
+	if sv.sharedFields == nil {
+		sv.sharedFields = map[string]bool{}
+	}
+	for _, field := range fields {
+		sv.sharedFields[field] = true
+	}
+
+	return sv
+}
+
+// isShared reports whether the field is expected to be shared between the
+// clone and the original, by the registration or by the struct tag
+func (sv *StructVerifier) isShared(field string) bool {
+	if sv.sharedFields[field] {
+		return true
+	}
+
+	f, ok := typeFieldByPath(reflect.TypeOf(sv.creator()).Elem(), field)
+	return ok && f.Tag.Get(sharedTagKey) == sharedTagValue
+}
+
+// checkSharedKnown checks that all fields registered as shared exist in the structure
+func (sv *StructVerifier) checkSharedKnown() error {
+	t := reflect.TypeOf(sv.creator()).Elem()
+	for _, field := range sortedKeys(reflect.ValueOf(sv.sharedFields)) {
+		if f, ok := typeFieldByPath(t, field.String()); !ok || !verifiable(f) {
+			return &ErrSVFieldNotFound{newErrSV("field %q expected to be shared was not found in the structure %v",
+				field.String(), t)}
+		}
+	}
+
+	return nil
+}
+
+// verifySharedField creates a clone of orig and checks that the field refers
+// to the same data in the clone and the original. The clone is not changed
+func (sv *StructVerifier) verifySharedField(orig any, field string) error {
+	clone, err := sv.callCloner(orig, field)
+	if err != nil {
+		return err
+	}
+
+	if !sv.equal(orig, clone) {
+		return &ErrSVCloneOrigNotEqual{newErrSV("newly created clone is not the same as the original:" +
+			" orig - %s, clone - %s", sv.dump(orig), sv.dump(clone))}
+	}
+
+	of, cf := fieldByPath(reflect.ValueOf(orig).Elem(), field), fieldByPath(reflect.ValueOf(clone).Elem(), field)
+	shared, ok := sameData(of, cf)
+	if !ok {
+		return &ErrSVNotShared{
+			structVerifierError:	newErrSV("field %q expected to be shared has type %q that cannot be shared",
+										field, of.Type()),
+			Field:					field,
+		}
+	}
+	if !shared {
+		return &ErrSVNotShared{
+			structVerifierError:	newErrSV("CLONE field %q is expected to be SHARED with the ORIGINAL," +
+										" but it is a copy: %s", field, sv.dump(cf.Interface())),
+			Field:					field,
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// sameData reports whether orig and clone refer to the same data. The second
+// returned value is false if values of such type cannot refer to shared data
+func sameData(orig, clone reflect.Value) (shared, ok bool) {
+	switch orig.Kind() { //nolint:exhaustive	// other kinds cannot be shared
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return !orig.IsNil() && orig.Pointer() == clone.Pointer(), true
+
+	case reflect.Slice:
+		return orig.Cap() != 0 && orig.Pointer() == clone.Pointer(), true
+
+	case reflect.String:
+		os, cs := orig.String(), clone.String()
+		return len(os) != 0 && stringData(os) == stringData(cs), true
+
+	case reflect.Interface:
+		if orig.IsNil() || clone.IsNil() || orig.Elem().Type() != clone.Elem().Type() {
+			return false, true
+		}
+		return sameData(orig.Elem(), clone.Elem())
+	}
+
+	return false, false
+}
+
+// stringData returns the pointer to the bytes of the string s
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data //nolint:staticcheck	// unsafe.StringData requires Go 1.20
+}
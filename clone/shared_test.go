@@ -0,0 +1,84 @@
+package clone
+
+import (
+	"errors"
+	"testing"
+)
+
+type testLookup struct {
+	Name	string
+	Vals	[]int
+	Table	map[string]int	`clone:"shared"`
+}
+
+// cloneLookup returns a clone of testLookup, if shareTable is true,
+// the Table field is shared with the original
+func cloneLookup(x any, shareTable bool) any {
+	orig := x.(*testLookup)	//nolint:forcetypeassert
+
+	rv := &testLookup{Name: orig.Name, Vals: append([]int(nil), orig.Vals...), Table: orig.Table}
+	if !shareTable && orig.Table != nil {
+		rv.Table = make(map[string]int, len(orig.Table))
+		for k, v := range orig.Table {
+			rv.Table[k] = v
+		}
+	}
+
+	return rv
+}
+
+func TestExpectSharedTag(t *testing.T) {
+	if err := NewStructVerifier(
+		func() any { return &testLookup{} },
+		func(x any) any { return cloneLookup(x, true) },
+	).ChangeAllFields().InsertMapKeys().Verify(); err != nil {
+		t.Errorf("verification of the intentionally shared field failed: %v", err)
+	}
+
+	err := NewStructVerifier(
+		func() any { return &testLookup{} },
+		func(x any) any { return cloneLookup(x, false) },
+	).Verify()
+	var nsErr *ErrSVNotShared
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because the Table field is copied")
+	case errors.As(err, &nsErr):
+		if nsErr.Field != "Table" {
+			t.Errorf("want Table field reported, got - %q", nsErr.Field)
+		}
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNotShared", err, err)
+	}
+}
+
+func TestExpectShared(t *testing.T) {
+	type testShared struct {
+		Name	string
+		Vals	[]int
+		Count	int
+	}
+	shareVals := func(x any) any {
+		rv := *x.(*testShared)	//nolint:forcetypeassert
+		return &rv
+	}
+
+	if err := NewStructVerifierWith(
+		func() any { return &testShared{} }, shareVals,
+		WithExpectShared("Vals"),
+	).Verify(); err != nil {
+		t.Errorf("verification of the registered shared field failed: %v", err)
+	}
+
+	// Field of type that cannot be shared
+	err := NewStructVerifier(func() any { return &testShared{} }, shareVals).ExpectShared("Vals", "Count").Verify()
+	if !errors.As(err, new(*ErrSVNotShared)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVNotShared", err, err)
+	}
+
+	// Unknown field
+	err = NewStructVerifier(func() any { return &testShared{} }, shareVals).ExpectShared("Unknown").Verify()
+	if !errors.As(err, new(*ErrSVFieldNotFound)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVFieldNotFound", err, err)
+	}
+}
@@ -0,0 +1,39 @@
+package clone
+
+import (
+	"math/rand"
+)
+
+/*
+SetShuffleFields makes [StructVerifier.Verify] process fields in the random
+order instead of the declaration order. The order is determined by the seed,
+so the same seed produces the same order on each verification, and a failure
+found with some seed can be reproduced.
+
+The per-field checks are independent of the order, so the shuffling mainly
+benefits the phase changing all fields at once (see
+[StructVerifier.ChangeAllFields]), where interaction bugs may depend on the
+order of changes. Run the verification with different seeds to explore more
+orderings.
+*/
+func (sv *StructVerifier) SetShuffleFields(seed int64) *StructVerifier {
+	sv.shuffle = true
+	sv.shuffleSeed = seed
+	return sv
+}
+
+// shuffleFields returns the fields in the order used for verification, the
+// fields are shuffled if required
+func (sv *StructVerifier) shuffleFields(fields []string) []string {
+	if !sv.shuffle {
+		return fields
+	}
+
+	rv := append([]string(nil), fields...)
+	//nolint:gosec	// Deterministic order is required, not the cryptographic randomness
+	rand.New(rand.NewSource(sv.shuffleSeed)).Shuffle(len(rv), func(i, j int) {
+		rv[i], rv[j] = rv[j], rv[i]
+	})
+
+	return rv
+}
@@ -0,0 +1,41 @@
+package clone
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type testWide struct {
+	A, B, C, D, E, F, G, H	int
+}
+
+func TestShuffleFields(t *testing.T) {
+	fieldsOrder := func(opts ...Option) []string {
+		var order []string
+		sv := NewStructVerifierWith(
+			func() any { return &testWide{} },
+			func(x any) any { rv := *x.(*testWide); return &rv },	//nolint:forcetypeassert
+			opts...,
+		).OnFieldStart(func(field string) { order = append(order, field) })
+		if err := sv.Verify(); err != nil {
+			t.Fatalf("verification failed: %v", err)
+		}
+		return order
+	}
+
+	declared := fieldsOrder()
+	shuffled := fieldsOrder(WithShuffleFields(1), WithChangeAllFields())
+
+	if reflect.DeepEqual(declared, shuffled) {
+		t.Errorf("fields were not shuffled: %v", shuffled)
+	}
+	if again := fieldsOrder(WithShuffleFields(1)); !reflect.DeepEqual(again, shuffled) {
+		t.Errorf("order with the same seed differs: %v, want - %v", again, shuffled)
+	}
+
+	sort.Strings(shuffled)
+	if !reflect.DeepEqual(declared, shuffled) {
+		t.Errorf("shuffled fields %v are not a permutation of %v", shuffled, declared)
+	}
+}
@@ -0,0 +1,138 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrSVStdCloneMismatch represents an error that occurs when the result of
+// the cloner function differs from the result of cloning by the standard
+// library functions, see [CompareStdClone]. Fields contains descriptions of
+// the differing fields, each starts with "<input>: <field>".
+type ErrSVStdCloneMismatch struct {
+	structVerifierError
+	Fields	[]string
+}
+
+/*
+CompareStdClone compares the result of the cloner function with the reference
+clone created by applying the [slices.Clone] and [maps.Clone] functions to the
+corresponding slice and map fields of the original (other fields are copied
+as is). It helps to make sure that migration of hand-written Clone methods to
+the standard library functions does not change their behavior, or vice versa.
+
+The comparison is performed field by field for three originals:
+
+  - filled - all exported fields are filled automatically as by [StructVerifier.Verify],
+    the options are used to configure the filling
+  - zero - the value returned by the creator function, usually with nil slices and maps
+  - empty - the value returned by the creator function, with all nil slices
+    and maps of exported fields replaced by empty ones
+
+Note that slices.Clone and maps.Clone preserve nil values and keep empty values
+empty, so a cloner that converts nil to empty (or vice versa) is reported.
+
+If some fields differ, the *[ErrSVStdCloneMismatch] error containing all
+differing fields is returned.
+*/
+func CompareStdClone(creator CreatorFunc, cloner ClonerFunc, opts ...Option) error {
+	sv := NewStructVerifierWith(creator, cloner, opts...)
+
+	filled, err := sv.autoFill()
+	if err != nil {
+		return &ErrSVOrigFill{newErrSV("cannot autofill original structure: %w", err)}
+	}
+
+	empty := creator()
+	fillEmpty(reflect.ValueOf(empty).Elem())
+
+	var diffs []string
+	for _, input := range []struct {
+		name	string
+		orig	any
+	}{
+		{"filled", filled},
+		{"zero", creator()},
+		{"empty", empty},
+	} {
+		clone, err := sv.callCloner(input.orig, "")
+		if err != nil {
+			return err
+		}
+
+		ref := stdClone(input.orig)
+		for _, field := range structFields(input.orig) {
+			cf := fieldByPath(reflect.ValueOf(clone).Elem(), field).Interface()
+			rf := fieldByPath(reflect.ValueOf(ref).Elem(), field).Interface()
+			if !sv.equal(cf, rf) {
+				diffs = append(diffs, fmt.Sprintf("%s: %s (clone - %s, std - %s)",
+					input.name, field, sv.dump(cf), sv.dump(rf)))
+			}
+		}
+	}
+
+	if diffs != nil {
+		return &ErrSVStdCloneMismatch{
+			structVerifierError:	newErrSV("CLONE differs from the standard library clone in fields: %s",
+										strings.Join(diffs, "; ")),
+			Fields:					diffs,
+		}
+	}
+
+	// OK
+	return nil
+}
+
+// stdClone returns a copy of the structure pointed by si where all exported
+// slice and map fields are cloned the same way as slices.Clone and maps.Clone do
+func stdClone(si any) any {
+	src := reflect.ValueOf(si).Elem()
+	dst := reflect.New(src.Type())
+	dst.Elem().Set(src)
+
+	s := dst.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		if !isExported(s.Type().Field(i).Name) {
+			continue
+		}
+
+		f := s.Field(i)
+		if f.Kind() != reflect.Slice && f.Kind() != reflect.Map || f.IsNil() {
+			// Nothing to clone, nil values are preserved
+			continue
+		}
+
+		if f.Kind() == reflect.Slice {
+			c := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+			reflect.Copy(c, f)
+			f.Set(c)
+			continue
+		}
+
+		c := reflect.MakeMapWithSize(f.Type(), f.Len())
+		iter := f.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		f.Set(c)
+	}
+
+	return dst.Interface()
+}
+
+// fillEmpty replaces nil slices and maps in exported fields of the structure s by empty ones
+func fillEmpty(s reflect.Value) {
+	for i := 0; i < s.NumField(); i++ {
+		if !isExported(s.Type().Field(i).Name) {
+			continue
+		}
+
+		switch f := s.Field(i); {
+		case f.Kind() == reflect.Slice && f.IsNil():
+			f.Set(reflect.MakeSlice(f.Type(), 0, 0))
+		case f.Kind() == reflect.Map && f.IsNil():
+			f.Set(reflect.MakeMap(f.Type()))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testStdStruct struct {
+	Name	string
+	IDs		[]int64
+	Tags	map[string]string
+}
+
+// cloneStdStruct returns a clone of testStdStruct, if nilToEmpty is true,
+// nil slices and maps of the original are cloned as empty ones
+func cloneStdStruct(x any, nilToEmpty bool) any {
+	orig, ok := x.(*testStdStruct)
+	if !ok {
+		panic(fmt.Sprintf("unsupported type to clone - %T, want - *testStdStruct", x))
+	}
+
+	rv := *orig
+
+	if orig.IDs != nil || nilToEmpty {
+		rv.IDs = make([]int64, len(orig.IDs))
+		copy(rv.IDs, orig.IDs)
+	}
+
+	if orig.Tags != nil || nilToEmpty {
+		rv.Tags = make(map[string]string, len(orig.Tags))
+		for k, v := range orig.Tags {
+			rv.Tags[k] = v
+		}
+	}
+
+	return &rv
+}
+
+func TestCompareStdClone(t *testing.T) {
+	creator := func() any { return &testStdStruct{} }
+
+	if err := CompareStdClone(creator, func(x any) any { return cloneStdStruct(x, false) }); err != nil {
+		t.Errorf("comparison with the standard library clone failed: %v", err)
+	}
+
+	err := CompareStdClone(creator, func(x any) any { return cloneStdStruct(x, true) })
+
+	var errStd *ErrSVStdCloneMismatch
+	if !errors.As(err, &errStd) {
+		t.Fatalf("got unexpected error %T (%v), want - *ErrSVStdCloneMismatch", err, err)
+	}
+
+	// Only the zero original has nil fields
+	if len(errStd.Fields) != 2 {
+		t.Errorf("got %d differing fields, want - 2: %v", len(errStd.Fields), err)
+	}
+}
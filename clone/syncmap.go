@@ -0,0 +1,129 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// syncMapType is the type of the sync.Map values
+var syncMapType = reflect.TypeOf((*sync.Map)(nil)).Elem()
+
+// syncMapTypes caches results of the hasSyncMap function
+var syncMapTypes sync.Map // map[reflect.Type]bool
+
+// isSyncMap returns true if t is the sync.Map type
+func isSyncMap(t reflect.Type) bool {
+	return t == syncMapType
+}
+
+// hasSyncMap returns true if values of type t may contain sync.Map values, such
+// values cannot be compared by reflect.DeepEqual, because the entries are stored
+// in unexported fields behind pointers to the internal structures
+func hasSyncMap(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	if v, ok := syncMapTypes.Load(t); ok {
+		return v.(bool) //nolint:forcetypeassert	// only bool values are stored
+	}
+
+	rv := findType(t, isSyncMap, map[reflect.Type]bool{})
+	syncMapTypes.Store(t, rv)
+
+	return rv
+}
+
+// syncMapOf returns the pointer to the sync.Map value v. It returns false if v
+// is not a sync.Map or its entries cannot be obtained, e.g. v is stored in an
+// unexported field
+func syncMapOf(v reflect.Value) (*sync.Map, bool) {
+	if !isSyncMap(v.Type()) || !v.CanInterface() {
+		return nil, false
+	}
+
+	if !v.CanAddr() {
+		// Need an addressable copy to call methods with pointer receivers,
+		// the copy refers to the same entries
+		c := reflect.New(v.Type())
+		c.Elem().Set(v)
+		v = c.Elem()
+	}
+
+	return v.Addr().Interface().(*sync.Map), true //nolint:forcetypeassert	// the type is checked above
+}
+
+// syncMapSnapshot returns the entries of the sync.Map value v as a regular map,
+// it can be compared and walked like other maps. It returns false if v is not
+// a sync.Map value, see syncMapOf
+func syncMapSnapshot(v reflect.Value) (reflect.Value, bool) {
+	m, ok := syncMapOf(v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	snapshot := map[any]any{}
+	m.Range(func(key, val any) bool {
+		snapshot[key] = val
+		return true
+	})
+
+	return reflect.ValueOf(snapshot), true
+}
+
+// syncMapValue creates a new sync.Map value with distinct entries, the keys and
+// values are produced by the filler as strings and integers. It returns false
+// if v is not a sync.Map value
+func (fl *filler) syncMapValue(v reflect.Value, path string) (reflect.Value, bool, error) {
+	if !isSyncMap(v.Type()) {
+		return reflect.Value{}, false, nil
+	}
+
+	m := &sync.Map{}
+	for i := 0; i < genericLen; i++ {
+		key, err := fl.value(reflect.New(reflect.TypeOf("")).Elem(), fmt.Sprintf("%s(key #%d)", path, i))
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		val, err := fl.value(reflect.New(reflect.TypeOf(0)).Elem(), fmt.Sprintf("%s[%v]", path, key))
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		m.Store(key.Interface(), val.Interface())
+	}
+
+	return reflect.ValueOf(m).Elem(), true, nil
+}
+
+// changeSyncMap stores a changed value for the first key of the sync.Map value v
+// in sorted order, a new entry is added if there is no value that can be changed.
+// It returns false if v is not a sync.Map value
+func (ch *changer) changeSyncMap(v reflect.Value) bool {
+	snapshot, ok := syncMapSnapshot(v)
+	if !ok || !v.CanAddr() {
+		// Entries of a copy are changed only if they are already shared
+		return false
+	}
+	m, _ := syncMapOf(v)
+
+	for _, key := range sortedKeys(snapshot) {
+		cur := snapshot.MapIndex(key)
+		if cur.IsNil() {
+			continue
+		}
+
+		// Make a changeable copy of the stored value
+		val := reflect.New(cur.Elem().Type()).Elem()
+		val.Set(cur.Elem())
+		if ch.change(val) {
+			m.Store(key.Interface(), val.Interface())
+			return true
+		}
+	}
+
+	// Add the entry with the key that is not used by the filler
+	m.Store(fmt.Sprintf("changed_%d", snapshot.Len()), snapshot.Len())
+
+	return true
+}
@@ -0,0 +1,112 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type testCache struct {
+	Name	string
+	Entries	sync.Map
+	Index	*sync.Map
+}
+
+// copySyncMap copies entries of src to dst
+func copySyncMap(dst, src *sync.Map) {
+	src.Range(func(key, val any) bool {
+		dst.Store(key, val)
+		return true
+	})
+}
+
+// cloneCache returns a clone of testCache, shareIndex makes the clone to share
+// the Index map, copyEntries makes the clone to copy the Entries value as is,
+// sharing its internal state with the original
+func cloneCache(x any, shareIndex, copyEntries bool) any {
+	orig := x.(*testCache)	//nolint:forcetypeassert
+
+	rv := &testCache{Name: orig.Name}
+	if copyEntries {
+		// The same as *orig, without the warning about copying locks
+		reflect.ValueOf(&rv.Entries).Elem().Set(reflect.ValueOf(&orig.Entries).Elem())
+	} else {
+		copySyncMap(&rv.Entries, &orig.Entries)
+	}
+
+	switch {
+	case orig.Index == nil:
+		// Nothing to copy
+	case shareIndex:
+		rv.Index = orig.Index
+	default:
+		rv.Index = &sync.Map{}
+		copySyncMap(rv.Index, orig.Index)
+	}
+
+	return rv
+}
+
+func TestCloneSyncMaps(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testCache{} },
+		func(x any) any { return cloneCache(x, false, false) },
+	)
+
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification of structure with sync.Map fields failed: %v", err)
+	}
+	x, _ := sv.SampleFilled()
+	n := 0
+	x.(*testCache).Entries.Range(func(_, _ any) bool { n++; return true })	//nolint:forcetypeassert
+	if n != genericLen {
+		t.Errorf("sync.Map is filled by %d entries, want - %d", n, genericLen)
+	}
+
+	// Entries of sync.Map values are compared instead of internal structures
+	a, b := &testCache{}, &testCache{}
+	a.Entries.Store("key", 1)
+	b.Entries.Store("key", 1)
+	if !sv.equal(a, b) {
+		t.Errorf("sync.Map values with the same entries are not equal")
+	}
+	b.Entries.Store("key", 2)
+	if sv.equal(a, b) {
+		t.Errorf("sync.Map values with different entries are equal")
+	}
+}
+
+func TestCloneSyncMapsSharedPointer(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testCache{} },
+		func(x any) any { return cloneCache(x, true, false) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the Index map with the original")
+	case errors.As(err, new(*ErrSVSharedPointer)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSharedPointer", err, err)
+	}
+}
+
+func TestCloneSyncMapsCopiedValue(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &testCache{} },
+		func(x any) any { return cloneCache(x, false, true) },
+	)
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because clone shares the Entries state with the original")
+	case errors.As(err, new(*ErrSVOrigChanged)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+}
@@ -0,0 +1,73 @@
+package clone
+
+import (
+	"reflect"
+)
+
+// typeMismatch walks through the original and the reference values in parallel
+// and returns the path to the first interface value holding values of different
+// types in orig and ref. Elements of slices and maps, as well as values pointed
+// by pointers, get the path of the field that contains them
+func typeMismatch(orig, ref reflect.Value, path string, seen map[uintptr]bool) (string, reflect.Type, reflect.Type, bool) {
+	if !orig.IsValid() || !ref.IsValid() {
+		return "", nil, nil, false
+	}
+
+	switch orig.Kind() { //nolint:exhaustive	// other kinds cannot hold values of different types
+	case reflect.Interface:
+		if orig.IsNil() || ref.IsNil() {
+			if orig.IsNil() != ref.IsNil() {
+				return path, elemType(orig), elemType(ref), true
+			}
+			return "", nil, nil, false
+		}
+		if orig.Elem().Type() != ref.Elem().Type() {
+			return path, orig.Elem().Type(), ref.Elem().Type(), true
+		}
+		return typeMismatch(orig.Elem(), ref.Elem(), path, seen)
+
+	case reflect.Pointer:
+		if orig.IsNil() || ref.IsNil() || seen[orig.Pointer()] {
+			return "", nil, nil, false
+		}
+		seen[orig.Pointer()] = true
+		return typeMismatch(orig.Elem(), ref.Elem(), path, seen)
+
+	case reflect.Struct:
+		for i := 0; i < orig.NumField(); i++ {
+			name := orig.Type().Field(i).Name
+			if path != "" {
+				name = path + "." + name
+			}
+			if p, ot, rt, ok := typeMismatch(orig.Field(i), ref.Field(i), name, seen); ok {
+				return p, ot, rt, true
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < orig.Len() && i < ref.Len(); i++ {
+			if p, ot, rt, ok := typeMismatch(orig.Index(i), ref.Index(i), path, seen); ok {
+				return p, ot, rt, true
+			}
+		}
+
+	case reflect.Map:
+		for _, key := range sortedKeys(orig) {
+			if p, ot, rt, ok := typeMismatch(orig.MapIndex(key), ref.MapIndex(key), path, seen); ok {
+				return p, ot, rt, true
+			}
+		}
+	}
+
+	return "", nil, nil, false
+}
+
+// elemType returns the type of the value stored in the interface value v,
+// or nil if v is nil
+func elemType(v reflect.Value) reflect.Type {
+	if v.IsNil() {
+		return nil
+	}
+
+	return v.Elem().Type()
+}
@@ -0,0 +1,60 @@
+package clone
+
+import (
+	"reflect"
+)
+
+/*
+StrictSetters makes [StructVerifier.Verify] fail with *[ErrSVSetterNotVarying]
+if two different fields of the same type have equal values after filling.
+
+According to the [SetterCreator] contract, different fields of the same type
+must be filled by different values, otherwise some cloning errors cannot be
+detected. By default, such violation is only reported as a warning, see
+[StructVerifier.Warnings].
+*/
+func (sv *StructVerifier) StrictSetters() *StructVerifier {
+	sv.strictSetters = true
+	return sv
+}
+
+// Warnings returns the list of warnings found by the last call of
+// [StructVerifier.Verify]. The warnings are non-fatal problems of the verifier
+// configuration that weaken the verification.
+func (sv *StructVerifier) Warnings() []error {
+	return sv.warnings
+}
+
+// checkVarying checks that different fields of the same type of the filled
+// structure si have different values. Found problems are returned as an
+// error in strict mode, otherwise they are appended to the warnings list
+func (sv *StructVerifier) checkVarying(si any) error {
+	s := reflect.ValueOf(si).Elem()
+
+	// Indexes of already checked fields grouped by type
+	byType := map[reflect.Type][]int{}
+
+	for i := 0; i < s.NumField(); i++ {
+		if !verifiable(s.Type().Field(i)) || sv.skipped[s.Type().Field(i).Name] {
+			continue
+		}
+
+		f := s.Field(i)
+		for _, j := range byType[f.Type()] {
+			if !sv.equal(s.Field(j).Interface(), f.Interface()) {
+				continue
+			}
+
+			err := &ErrSVSetterNotVarying{newErrSV("fields %q and %q of type %q have the same value after filling: %s",
+				s.Type().Field(j).Name, s.Type().Field(i).Name, f.Type(), sv.dump(f.Interface()))}
+			if sv.strictSetters {
+				return err
+			}
+			sv.warnings = append(sv.warnings, err)
+		}
+
+		byType[f.Type()] = append(byType[f.Type()], i)
+	}
+
+	return nil
+}
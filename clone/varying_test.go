@@ -0,0 +1,85 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type varyingBools struct {
+	B1	bool
+	B2	bool
+}
+
+// Buggy setter - always returns the same value
+func constBoolSetter() Setter {
+	return func(v reflect.Value) any {
+		if _, ok := v.Interface().(bool); ok {
+			return true
+		}
+		return nil
+	}
+}
+
+func boolNegChanger(v reflect.Value) bool {
+	if b, ok := v.Interface().(bool); ok {
+		v.SetBool(!b)
+		return true
+	}
+	return false
+}
+
+func TestSetterNotVarying(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &varyingBools{} },
+		func(x any) any { rv := *x.(*varyingBools); return &rv },	//nolint:forcetypeassert
+	).AddSetters(constBoolSetter).AddChangers(boolNegChanger)
+
+	// Non-strict mode - only warning
+	if err := sv.Verify(); err != nil {
+		t.Errorf("verification in non-strict mode failed: %v", err)
+	}
+	if warns := sv.Warnings(); len(warns) != 1 || !errors.As(warns[0], new(*ErrSVSetterNotVarying)) {
+		t.Errorf("got unexpected warnings %v, want - single *ErrSVSetterNotVarying", warns)
+	}
+}
+
+func TestSetterNotVaryingStrict(t *testing.T) {
+	sv := NewStructVerifier(
+		func() any { return &varyingBools{} },
+		func(x any) any { rv := *x.(*varyingBools); return &rv },	//nolint:forcetypeassert
+	).AddSetters(constBoolSetter).AddChangers(boolNegChanger).StrictSetters()
+
+	err := sv.Verify()
+	switch {
+	case err == nil:
+		t.Errorf("returned no error but must fail, because setter always returns the same value")
+	case errors.As(err, new(*ErrSVSetterNotVarying)):
+		// OK, expected error
+	default:
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVSetterNotVarying", err, err)
+	}
+}
+
+func TestEmbSettersVarying(t *testing.T) {
+	type sameTypes struct {
+		I1, I2		int
+		L1, L2		int64
+		R1, R2		rune
+		IS1, IS2	[]int
+		LS1, LS2	[]int64
+		RS1, RS2	[]rune
+		SS1, SS2	[]string
+		M1, M2		map[string]any
+		P1, P2		*int
+	}
+
+	sv := NewStructVerifier(func() any { return &sameTypes{} }, func(x any) any { return x }).StrictSetters()
+	orig, err := sv.autoFill()
+	if err != nil {
+		t.Fatalf("cannot autofill structure: %v", err)
+	}
+	if err := sv.checkVarying(orig); err != nil {
+		t.Errorf("embedded setters produce the same values: %v", err)
+	}
+}
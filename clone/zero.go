@@ -0,0 +1,70 @@
+package clone
+
+import (
+	"reflect"
+)
+
+/*
+ZeroingChanger is an optional [Changer] that overwrites the entire contents of
+slices and maps by zero values of their element types, instead of modifying a
+single element as the embedded Changer functions do. It is more aggressive, so
+it maximizes the chance to detect storage shared between the original and the
+clone, e.g. a buffer shared in a copy-on-write manner. Use it explicitly:
+
+  sv.AddChangers(clone.ZeroingChanger)
+
+It handles slices and maps of any types, including named types. Empty values
+and values that already contain only zero elements are not handled, so they
+are passed to the next Changer functions.
+*/
+func ZeroingChanger(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive	// only slices and maps are handled
+	case reflect.Slice:
+		if v.Len() == 0 || allZero(v) {
+			return false
+		}
+
+		zero := reflect.Zero(v.Type().Elem())
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(zero)
+		}
+
+		return true
+
+	case reflect.Map:
+		if v.Len() == 0 || allZero(v) {
+			return false
+		}
+
+		zero := reflect.Zero(v.Type().Elem())
+		for _, key := range v.MapKeys() {
+			v.SetMapIndex(key, zero)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// allZero returns true if all elements of the slice or values of the map v are zero values
+func allZero(v reflect.Value) bool {
+	if v.Kind() == reflect.Map {
+		iter := v.MapRange()
+		for iter.Next() {
+			if !iter.Value().IsZero() {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if !v.Index(i).IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
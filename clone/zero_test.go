@@ -0,0 +1,60 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestZeroingChanger(t *testing.T) {
+	type bufStruct struct {
+		Buf		[]int
+		Counts	map[string]int
+	}
+
+	// cloneBuf returns a clone of bufStruct, if shareBuf is true, Buf is shared with the original
+	cloneBuf := func(x any, shareBuf bool) any {
+		orig := x.(*bufStruct)	//nolint:forcetypeassert
+		rv := *orig
+		if !shareBuf {
+			rv.Buf = append([]int(nil), orig.Buf...)
+		}
+		rv.Counts = make(map[string]int, len(orig.Counts))
+		for k, v := range orig.Counts {
+			rv.Counts[k] = v
+		}
+		return &rv
+	}
+	countsSetter := func() Setter {
+		var n int
+		return func(v reflect.Value) any {
+			if _, ok := v.Interface().(map[string]int); !ok {
+				return nil
+			}
+			n++
+			return map[string]int{"a": n, "b": n + 1}
+		}
+	}
+	creator := func() any { return &bufStruct{} }
+
+	if err := NewStructVerifier(creator, func(x any) any { return cloneBuf(x, false) }).
+		AddSetters(countsSetter).AddChangers(ZeroingChanger).Verify(); err != nil {
+		t.Errorf("verification with zeroing changer failed: %v", err)
+	}
+
+	if err := NewStructVerifier(creator, func(x any) any { return cloneBuf(x, true) }).
+		AddSetters(countsSetter).AddChangers(ZeroingChanger).Verify(); !errors.As(err, new(*ErrSVOrigChanged)) {
+		t.Errorf("got unexpected error %T (%v), want - *ErrSVOrigChanged", err, err)
+	}
+
+	// All elements must be zeroed
+	buf := []int{1, 2, 3}
+	if !ZeroingChanger(reflect.ValueOf(buf)) || !reflect.DeepEqual(buf, []int{0, 0, 0}) {
+		t.Errorf("zeroing changer did not zero all elements: %v", buf)
+	}
+
+	// Values without non-zero elements are not handled
+	if ZeroingChanger(reflect.ValueOf([]int{0, 0})) || ZeroingChanger(reflect.ValueOf(map[string]int{})) {
+		t.Errorf("zeroing changer handled the value without non-zero elements")
+	}
+}
@@ -0,0 +1,61 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+PrintChan outputs the state of the channel ch: its type and the number of
+buffered elements (length) and the buffer size (capacity). The channel is
+never received from, so the output does not change its contents. For example,
+
+  ch := make(chan int, 5)
+  ch <- 1
+  ch <- 2
+  debug.PrintChan(ch)
+
+will produce:
+
+  chan int(2:5)
+
+The type is printed if [PrintType] is set, the length and capacity are printed
+if [PrintLenCap] is set, if none of them is set, both are printed. The length
+and capacity of nil channels are printed as (nil). Other flags are ignored.
+
+PrintChan returns an error if ch is not a channel, nothing is printed in this case.
+*/
+func PrintChan(ch any, flagsVariadic ...PrintFlags) error {
+	flags := mergeFlags(flagsVariadic)
+
+	cv := reflect.ValueOf(ch)
+	if cv.Kind() != reflect.Chan {
+		return fmt.Errorf("PrintChan: argument of type %T is not a channel", ch)
+	}
+
+	if flags.Not(PrintType | PrintLenCap) {
+		// Print everything by default
+		flags |= PrintType | PrintLenCap
+	}
+
+	buf := &strings.Builder{}
+
+	if flags.Is(PrintType) {
+		fmt.Fprintf(buf, "%T", ch)
+	}
+
+	if flags.Is(PrintLenCap) {
+		if cv.IsNil() {
+			buf.WriteString("(nil)")
+		} else {
+			fmt.Fprintf(buf, "(%d:%d)", cv.Len(), cv.Cap())
+		}
+	}
+
+	buf.WriteString("\n")
+
+	writeOutput(buf.String())
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package debug
+
+import (
+	"fmt"
+)
+
+func ExamplePrintChan() {
+	ch := make(chan string, 5)
+	ch <- "one"
+	ch <- "two"
+
+	_ = PrintChan(ch)
+	_ = PrintChan((<-chan string)(ch), PrintLenCap)
+	_ = PrintChan(make(chan<- int), PrintType)
+
+	var nilChan chan error
+	_ = PrintChan(nilChan)
+
+	// The channel has not been drained
+	fmt.Println(<-ch, <-ch)
+
+	if err := PrintChan([]int{}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// chan string(2:5)
+	// (2:5)
+	// chan<- int
+	// chan error(nil)
+	// one two
+	// PrintChan: argument of type []int is not a channel
+}
@@ -0,0 +1,83 @@
+package debug
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+PrintCSV outputs a slice as CSV data, e.g. to import debug output into
+spreadsheets. For slices of structures, the first record is a header with the
+names of exported fields of the structure, then one record per element follows
+with the values of the fields. Elements of other types are output one per
+record. For example,
+
+  type user struct {
+      Name  string
+      Age   int
+  }
+  debug.PrintCSV([]user{ {"Alice", 31}, {"Bob, Jr.", 7} })
+
+will produce:
+
+  Name,Age
+  Alice,31
+  "Bob, Jr.",7
+
+The values are quoted by the [encoding/csv] package if required. The flags are
+the same as used by [PrintSlice], but only [PrintGoSyntax], [PrintAddr],
+[PrintTimeLayout] and [PrintStringer] affect the output of values.
+
+PrintCSV returns an error if slice is not a slice (or array) or the structure
+has no exported fields, nothing is printed in this case.
+*/
+func PrintCSV(slice any, flagsVariadic ...PrintFlags) error {
+	flags := mergeFlags(flagsVariadic)
+
+	sv := reflect.ValueOf(slice)
+	if sv.Kind() != reflect.Slice && sv.Kind() != reflect.Array {
+		return fmt.Errorf("PrintCSV: argument of type %T is not a slice", slice)
+	}
+
+	buf := &strings.Builder{}
+	w := csv.NewWriter(buf)
+
+	st := sv.Type().Elem()
+	if st.Kind() != reflect.Struct {
+		// One value per record
+		for n := 0; n < sv.Len(); n++ {
+			// Errors are not possible on writing to strings.Builder
+			_ = w.Write([]string{formatValue(sv.Index(n).Interface(), flags)})
+		}
+	} else {
+		// Indexes of exported fields
+		var fields []int
+		header := []string{}
+		for i := 0; i < st.NumField(); i++ {
+			if st.Field(i).IsExported() {
+				fields = append(fields, i)
+				header = append(header, st.Field(i).Name)
+			}
+		}
+		if fields == nil {
+			return fmt.Errorf("PrintCSV: structure %v has no exported fields", st)
+		}
+
+		_ = w.Write(header)
+		for n := 0; n < sv.Len(); n++ {
+			record := make([]string, 0, len(fields))
+			for _, i := range fields {
+				record = append(record, formatValue(sv.Index(n).Field(i).Interface(), flags))
+			}
+			_ = w.Write(record)
+		}
+	}
+
+	w.Flush()
+
+	writeOutput(buf.String())
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package debug
+
+import (
+	"fmt"
+)
+
+func ExamplePrintCSV() {
+	type point struct { X, Y int }
+	type eventInfo struct {
+		Cond	bool
+		Amount	int
+		Descr	string
+		Pos		point
+		notes	string
+	}
+	slice := []eventInfo{
+		{Cond: true, Amount: 5, Descr: "positive, condition", Pos: point{X: 15, Y: 83}},
+		{Amount: 125, Descr: `"quoted"`, notes: "not printed"},
+	}
+
+	if err := PrintCSV(slice); err != nil {
+		fmt.Println(err)
+	}
+	if err := PrintCSV([]string{"one", "two\nlines"}, PrintGoSyntax); err != nil {
+		fmt.Println(err)
+	}
+	if err := PrintCSV(42); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// Cond,Amount,Descr,Pos
+	// true,5,"positive, condition",{15 83}
+	// false,125,"""quoted""",{0 0}
+	// """one"""
+	// """two\nlines"""
+	// PrintCSV: argument of type int is not a slice
+}
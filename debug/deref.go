@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// derefValue returns the value pointed by v if v is a pointer, multiple
+// levels of indirection are dereferenced. It returns the invalid value if
+// v is a nil pointer at any level
+func derefValue(v any) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return reflect.Value{}, false
+	}
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, true
+		}
+		rv = rv.Elem()
+	}
+
+	return rv, true
+}
+
+// formatDeref returns the dereferenced value dv, see PrintDeref. Structures
+// are rendered as their exported fields with names
+func formatDeref(dv reflect.Value, flags PrintFlags) string {
+	if !dv.IsValid() {
+		return nilToken
+	}
+
+	valFmt := "%v"
+	if flags.Is(PrintGoSyntax) {
+		valFmt = "%#v"
+	}
+
+	if dv.Kind() != reflect.Struct {
+		return fmt.Sprintf(valFmt, dv.Interface())
+	}
+
+	buf := &strings.Builder{}
+	if flags.Is(PrintGoSyntax) {
+		buf.WriteString(dv.Type().String())
+	}
+
+	sep := " "
+	if flags.Is(PrintGoSyntax) {
+		sep = ", "
+	}
+
+	buf.WriteString("{")
+	n := 0
+	for i := 0; i < dv.NumField(); i++ {
+		if !dv.Type().Field(i).IsExported() {
+			continue
+		}
+
+		if n != 0 {
+			buf.WriteString(sep)
+		}
+		n++
+
+		fmt.Fprintf(buf, "%s:" + valFmt, dv.Type().Field(i).Name, dv.Field(i).Interface())
+	}
+	buf.WriteString("}")
+
+	return buf.String()
+}
@@ -0,0 +1,203 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Placeholders of the diff output
+const (
+	diffRoot	= "value"		// path of the whole compared values
+	diffMissing	= "<missing>"	// element or key absent in one of the values
+)
+
+// differ collects differences of two values
+type differ struct {
+	flags	PrintFlags
+	lines	[]string
+	visited	map[[2]uintptr]bool	// pairs of compared pointers, to stop on cycles
+}
+
+/*
+PrintDiff outputs the differences between a and b in the unified diff manner:
+each differing element is printed by two lines, the value of a prefixed by "-"
+and the value of b prefixed by "+", both followed by the path to the element.
+Pointers and interfaces are dereferenced, structures are compared field by
+field (including unexported fields), slices and arrays element by element and
+maps key by key, in order of sorted keys. For example,
+
+  type config struct {
+      Name   string
+      Ports  []int
+      Opts   map[string]bool
+  }
+  debug.PrintDiff(
+      config{"srv", []int{80, 443}, map[string]bool{"tls": true}},
+      config{"srv", []int{80, 8443, 9000}, map[string]bool{"tls": false}},
+  )
+
+will produce:
+
+  - Ports[1]: 443
+  + Ports[1]: 8443
+  - Ports[2]: <missing>
+  + Ports[2]: 9000
+  - Opts[tls]: true
+  + Opts[tls]: false
+
+Elements absent in one of the values are printed as <missing>, the whole
+values are denoted by the "value" path. If the values are equal, "no
+differences" is printed. The flags are the same as used by [PrintSlice], but
+only [PrintGoSyntax], [PrintAddr], [PrintTimeLayout] and [PrintStringer]
+affect the output of values.
+
+PrintDiff is useful to see what exactly diverged between two large values,
+e.g. the original and the reference structures reported by the clone
+verification errors.
+*/
+func PrintDiff(a, b any, flagsVariadic ...PrintFlags) {
+	d := &differ{flags: mergeFlags(flagsVariadic), visited: map[[2]uintptr]bool{}}
+	d.diff(reflect.ValueOf(a), reflect.ValueOf(b), "")
+
+	if d.lines == nil {
+		writeOutput("no differences\n")
+		return
+	}
+
+	writeOutput(strings.Join(d.lines, "\n") + "\n")
+}
+
+// diff compares a and b at the path and collects their differences
+func (d *differ) diff(a, b reflect.Value, path string) {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		if a.IsValid() != b.IsValid() || a.IsValid() && !d.equal(a, b) {
+			d.add(path, d.format(a), d.format(b))
+		}
+		return
+	}
+
+	switch a.Kind() { //nolint:exhaustive	// other kinds are compared as a whole
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			break
+		}
+		if a.Kind() == reflect.Pointer {
+			pair := [2]uintptr{a.Pointer(), b.Pointer()}
+			if a.Pointer() == b.Pointer() || d.visited[pair] {
+				return
+			}
+			d.visited[pair] = true
+		}
+		d.diff(a.Elem(), b.Elem(), path)
+		return
+
+	case reflect.Struct:
+		if a.NumField() == 0 {
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			d.diff(a.Field(i), b.Field(i), diffJoin(path, a.Type().Field(i).Name))
+		}
+		return
+
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			break
+		}
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			ip := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				d.add(ip, diffMissing, d.format(b.Index(i)))
+			case i >= b.Len():
+				d.add(ip, d.format(a.Index(i)), diffMissing)
+			default:
+				d.diff(a.Index(i), b.Index(i), ip)
+			}
+		}
+		return
+
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			break
+		}
+		for _, key := range mapKeysUnion(a, b) {
+			kp := fmt.Sprintf("%s[%v]", path, key)
+			av, bv := a.MapIndex(key), b.MapIndex(key)
+			switch {
+			case !av.IsValid():
+				d.add(kp, diffMissing, d.format(bv))
+			case !bv.IsValid():
+				d.add(kp, d.format(av), diffMissing)
+			default:
+				d.diff(av, bv, kp)
+			}
+		}
+		return
+	}
+
+	if !d.equal(a, b) {
+		d.add(path, d.format(a), d.format(b))
+	}
+}
+
+// equal reports whether a and b are deeply equal
+func (d *differ) equal(a, b reflect.Value) bool {
+	if a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	// Values of unexported fields
+	return fmt.Sprintf("%#v", a) == fmt.Sprintf("%#v", b)
+}
+
+// format returns the formatted value v
+func (d *differ) format(v reflect.Value) string {
+	if !v.IsValid() {
+		return nilToken
+	}
+	if !v.CanInterface() {
+		// Values of unexported fields cannot be passed as any
+		if d.flags.Is(PrintGoSyntax) {
+			return fmt.Sprintf("%#v", v)
+		}
+		return fmt.Sprint(v)
+	}
+
+	return formatValue(v.Interface(), d.flags)
+}
+
+// add adds the difference at the path to the output lines
+func (d *differ) add(path, a, b string) {
+	if path == "" {
+		path = diffRoot
+	}
+
+	d.lines = append(d.lines, "- " + path + ": " + a, "+ " + path + ": " + b)
+}
+
+// diffJoin returns the path of the field name of the structure at the path
+func diffJoin(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// mapKeysUnion returns keys of both maps a and b sorted by their string representation
+func mapKeysUnion(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	return keys
+}
@@ -0,0 +1,35 @@
+package debug
+
+func ExamplePrintDiff() {
+	type config struct {
+		Name	string
+		Ports	[]int
+		Opts	map[string]bool
+		limit	*int
+	}
+	one, two := 1, 2
+
+	PrintDiff(
+		config{"srv", []int{80, 443}, map[string]bool{"tls": true, "gzip": true}, &one},
+		config{"srv", []int{80, 8443, 9000}, map[string]bool{"tls": false, "h2": true}, &two},
+	)
+	PrintDiff([]string{"a"}, []string{"a"})
+	PrintDiff(1, "1", PrintGoSyntax)
+
+	// Output:
+	// - Ports[1]: 443
+	// + Ports[1]: 8443
+	// - Ports[2]: <missing>
+	// + Ports[2]: 9000
+	// - Opts[gzip]: true
+	// + Opts[gzip]: <missing>
+	// - Opts[h2]: <missing>
+	// + Opts[h2]: true
+	// - Opts[tls]: true
+	// + Opts[tls]: false
+	// - limit: 1
+	// + limit: 2
+	// no differences
+	// - value: 1
+	// + value: "1"
+}
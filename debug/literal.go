@@ -0,0 +1,269 @@
+package debug
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// literal renders values as Go composite literals, see PrintLiteral
+type literal struct {
+	buf		*strings.Builder
+	visited	map[uintptr]bool	// pointers on the current rendering path
+}
+
+// printLiteral outputs the slice as the composite literal, one element
+// per line if perLine is set
+func printLiteral(slice any, perLine bool) {
+	lit := &literal{buf: &strings.Builder{}, visited: map[uintptr]bool{}}
+
+	sv := reflect.ValueOf(slice)
+	if sv.IsNil() || !perLine || sv.Len() == 0 {
+		// Nil slice must be rendered with its type
+		lit.value(sv, true)
+	} else {
+		fmt.Fprintf(lit.buf, "%s{\n", sv.Type())
+		for i := 0; i < sv.Len(); i++ {
+			lit.buf.WriteString("\t")
+			lit.value(sv.Index(i), lit.needType(sv.Type().Elem()))
+			lit.buf.WriteString(",\n")
+		}
+		lit.buf.WriteString("}")
+	}
+
+	lit.buf.WriteString("\n")
+
+	writeOutput(lit.buf.String())
+}
+
+// needType returns true if values stored in the container with elements of
+// type t must be rendered with their types, i.e. t is an interface type
+func (lit *literal) needType(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface
+}
+
+// value renders v, typed means that the type of v cannot be derived from the
+// context, so untyped constants must be converted to the type of v
+func (lit *literal) value(v reflect.Value, typed bool) {
+	t := v.Type()
+
+	switch v.Kind() {
+	case reflect.Bool:
+		lit.constant(strconv.FormatBool(v.Bool()), t, typed, "bool")
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit.constant(strconv.FormatInt(v.Int(), 10), t, typed, "int")
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lit.constant(strconv.FormatUint(v.Uint(), 10), t, typed, "")
+
+	case reflect.Float32, reflect.Float64:
+		lit.float(v.Float(), t, typed)
+
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		lit.constant(fmt.Sprintf("complex(%s, %s)", floatConst(real(c)), floatConst(imag(c))), t, typed, "complex128")
+
+	case reflect.String:
+		lit.constant(strconv.Quote(v.String()), t, typed, "string")
+
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			lit.nilValue(t, typed)
+			return
+		}
+		if v.Kind() == reflect.Slice {
+			lit.elems(v)
+		} else {
+			lit.mapValue(v)
+		}
+
+	case reflect.Array:
+		lit.elems(v)
+
+	case reflect.Struct:
+		lit.structValue(v)
+
+	case reflect.Pointer:
+		lit.pointer(v, typed)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			lit.buf.WriteString("nil")
+			return
+		}
+		// The dynamic type is unknown from the context
+		lit.value(v.Elem(), true)
+
+	default:
+		// Channels, functions and unsafe pointers cannot be rendered as literals
+		lit.nilValue(t, typed)
+	}
+}
+
+// constant renders the constant c of type t, the conversion to t is omitted
+// if the type is derived from the context or t is the default type of the constant
+func (lit *literal) constant(c string, t reflect.Type, typed bool, defType string) {
+	if !typed || t.String() == defType {
+		lit.buf.WriteString(c)
+		return
+	}
+
+	fmt.Fprintf(lit.buf, "%s(%s)", t, c)
+}
+
+// float renders the floating-point value f of type t
+func (lit *literal) float(f float64, t reflect.Type, typed bool) {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		lit.constant(floatConst(f), t, typed, "float64")
+		return
+	}
+
+	// Not a constant, so the conversion is required for any type except float64
+	var expr string
+	switch {
+	case math.IsNaN(f):
+		expr = "math.NaN()"
+	case f > 0:
+		expr = "math.Inf(1)"
+	default:
+		expr = "math.Inf(-1)"
+	}
+
+	if t.String() == "float64" {
+		lit.buf.WriteString(expr)
+	} else {
+		fmt.Fprintf(lit.buf, "%s(%s)", t, expr)
+	}
+}
+
+// floatConst returns f as the floating-point constant
+func floatConst(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		// Keep the constant floating-point
+		s += ".0"
+	}
+
+	return s
+}
+
+// nilValue renders the nil value of type t
+func (lit *literal) nilValue(t reflect.Type, typed bool) {
+	if !typed {
+		lit.buf.WriteString("nil")
+		return
+	}
+
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Func || t.Kind() == reflect.Chan {
+		// Parentheses are required to convert to such types
+		fmt.Fprintf(lit.buf, "(%s)(nil)", t)
+	} else {
+		fmt.Fprintf(lit.buf, "%s(nil)", t)
+	}
+}
+
+// elems renders elements of the slice or array v
+func (lit *literal) elems(v reflect.Value) {
+	fmt.Fprintf(lit.buf, "%s{", v.Type())
+	typed := lit.needType(v.Type().Elem())
+	for i := 0; i < v.Len(); i++ {
+		if i != 0 {
+			lit.buf.WriteString(", ")
+		}
+		lit.value(v.Index(i), typed)
+	}
+	lit.buf.WriteString("}")
+}
+
+// mapValue renders entries of the map v sorted by rendered keys
+func (lit *literal) mapValue(v reflect.Value) {
+	t := v.Type()
+	keyTyped, valTyped := lit.needType(t.Key()), lit.needType(t.Elem())
+
+	type entry struct {
+		key, val string
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{
+			key:	lit.sub(iter.Key(), keyTyped),
+			val:	lit.sub(iter.Value(), valTyped),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	fmt.Fprintf(lit.buf, "%s{", t)
+	for i, e := range entries {
+		if i != 0 {
+			lit.buf.WriteString(", ")
+		}
+		fmt.Fprintf(lit.buf, "%s: %s", e.key, e.val)
+	}
+	lit.buf.WriteString("}")
+}
+
+// sub returns the rendered value v without writing it to the buffer
+func (lit *literal) sub(v reflect.Value, typed bool) string {
+	buf := lit.buf
+	defer func() { lit.buf = buf }()
+
+	lit.buf = &strings.Builder{}
+	lit.value(v, typed)
+
+	return lit.buf.String()
+}
+
+// structValue renders non-zero fields of the structure v
+func (lit *literal) structValue(v reflect.Value) {
+	t := v.Type()
+
+	fmt.Fprintf(lit.buf, "%s{", t)
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "_" || v.Field(i).IsZero() {
+			// Zero values are set by default
+			continue
+		}
+
+		if n != 0 {
+			lit.buf.WriteString(", ")
+		}
+		n++
+
+		fmt.Fprintf(lit.buf, "%s: ", f.Name)
+		lit.value(v.Field(i), lit.needType(f.Type))
+	}
+	lit.buf.WriteString("}")
+}
+
+// pointer renders the pointer v as the address of the composite literal, or as
+// the result of the function literal for pointers to other values. Pointers
+// making cycles are rendered as nil
+func (lit *literal) pointer(v reflect.Value, typed bool) {
+	if v.IsNil() || lit.visited[v.Pointer()] {
+		lit.nilValue(v.Type(), typed)
+		return
+	}
+
+	lit.visited[v.Pointer()] = true
+	defer delete(lit.visited, v.Pointer())
+
+	switch v.Elem().Kind() { //nolint:exhaustive	// other kinds cannot be addressed directly
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		if !v.Elem().IsZero() || v.Elem().Kind() == reflect.Struct || v.Elem().Kind() == reflect.Array {
+			lit.buf.WriteString("&")
+			lit.value(v.Elem(), false)
+			return
+		}
+	}
+
+	fmt.Fprintf(lit.buf, "func() %s { v := ", v.Type())
+	lit.value(v.Elem(), true)
+	lit.buf.WriteString("; return &v }()")
+}
@@ -0,0 +1,49 @@
+package debug
+
+import (
+	"math"
+)
+
+func Example_printSliceLiteral() {
+	PrintSlice([]int{1, 2, 3}, PrintLiteral)
+	PrintSlice([][]string{ {"a", "b"}, nil, {} }, PrintLiteral)
+	PrintSlice([]int(nil), PrintLiteral)
+
+	// Output:
+	// []int{1, 2, 3}
+	// [][]string{[]string{"a", "b"}, nil, []string{}}
+	// []int(nil)
+}
+
+func Example_printSliceLiteralStructs() {
+	type point struct { X, Y int }
+	type shape struct {
+		Name	string
+		Points	[]point
+		Center	*point
+		Attrs	map[string]any
+	}
+
+	slice := []shape{
+		{Name: "line", Points: []point{ {0, 0}, {3, 4} }, Attrs: map[string]any{"width": 1.5, "dashed": true, "id": int8(7)}},
+		{Name: "dot", Center: &point{1, 1}},
+	}
+
+	PrintSlice(slice, PrintLiteral, PrintValPerLine)
+
+	// Output:
+	// []debug.shape{
+	// 	debug.shape{Name: "line", Points: []debug.point{debug.point{}, debug.point{X: 3, Y: 4}}, Attrs: map[string]interface {}{"dashed": true, "id": int8(7), "width": 1.5}},
+	// 	debug.shape{Name: "dot", Center: &debug.point{X: 1, Y: 1}},
+	// }
+}
+
+func Example_printSliceLiteralValues() {
+	n := 5
+	slice := []any{nil, 2, uint(3), 1.0, float32(0.5), math.Inf(1), "s", &n, []byte("hi")}
+
+	PrintSlice(slice, PrintLiteral)
+
+	// Output:
+	// []interface {}{nil, 2, uint(3), 1.0, float32(0.5), math.Inf(1), "s", func() *int { v := 5; return &v }(), []uint8{104, 105}}
+}
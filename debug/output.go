@@ -0,0 +1,48 @@
+package debug
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	// Output of the Print* functions, nil means the current os.Stdout
+	output		io.Writer
+	// Mutex to protect the output
+	outputMtx	sync.Mutex
+)
+
+/*
+SetOutput sets the output destination of the Print* functions, by default it
+is [os.Stdout]. Passing nil restores the default output. For example, it can be used to check the output in unit tests:
+
+  buf := &bytes.Buffer{}
+  debug.SetOutput(buf)
+  defer debug.SetOutput(nil)
+
+  debug.PrintSlice([]int{1, 2, 3})
+  // buf.String() == "[#0:1 #1:2 #2:3]\n"
+
+SetOutput is safe for concurrent use with the Print* functions.
+*/
+func SetOutput(w io.Writer) {
+	outputMtx.Lock()
+	defer outputMtx.Unlock()
+
+	output = w
+}
+
+// writeOutput writes s to the output at once
+func writeOutput(s string) {
+	outputMtx.Lock()
+	defer outputMtx.Unlock()
+
+	w := output
+	if w == nil {
+		w = os.Stdout
+	}
+
+	// Errors are ignored the same way as fmt.Print* functions do
+	_, _ = io.WriteString(w, s)
+}
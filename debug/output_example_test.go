@@ -0,0 +1,21 @@
+package debug
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func ExampleSetOutput() {
+	// Redirect output to the buffer
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	// Restore default output
+	defer SetOutput(nil)
+
+	PrintSlice([]int{1, 2, 3}, PrintCommaSep)
+
+	fmt.Printf("captured: %q\n", buf.String())
+
+	// Output:
+	// captured: "[#0:1, #1:2, #2:3]\n"
+}
@@ -1,6 +1,11 @@
 package debug
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
 
 // PrintFlags is a set of flags that configure the Print* functions behavior.
 type PrintFlags uint32
@@ -25,6 +30,16 @@ const (
 	PrintLenCap		// print of the length and capacity of the argument before the actual content
 	PrintValType	// print the type of each element before print the element's content
 	PrintValPerLine	// print one element per line
+	PrintAddr		// print the address of pointer elements after the element's content
+	PrintRunLength	// collapse consecutive equal elements into a single "value (×count)" element
+	PrintPadIndex	// zero-pad the ordinal numbers of the items to the width of the largest one
+	PrintOffset		// print the byte offset of each item in hex before its ordinal number
+	PrintTimeLayout	// print time.Time items using the layout set by SetTimeLayout (time.RFC3339 by default)
+	PrintLiteral	// print the slice as a Go composite literal, e.g. []int{1, 2, 3}
+	PrintStringer	// print items implementing fmt.Stringer using their String method in all modes
+	PrintHexIndex	// print the ordinal numbers of the items in hex, e.g. #0xa
+	PrintDeref		// print values pointed by pointer items, structures as their exported fields
+	PrintStats		// print the count, min, max, sum and mean of numeric items on the line after the slice
 )
 
 /*
@@ -53,49 +68,126 @@ will produce:
   [#0:1 #1:2 #2:3 #3:4]
   [#0:one #1:two #2:three #3:four]
 
+Items implementing the [fmt.Stringer] interface (e.g. enum-like named integer
+types) are printed using their String method by default, but not in the
+Go-syntax mode. Use the [PrintStringer] flag to print them using the String
+method regardless of other flags, items of other types are printed as usual.
+
+Entries of maps are printed sorted by keys in both default and Go-syntax modes,
+including maps nested in elements, so the output does not depend on the map
+iteration order (the sorting is performed by the [fmt] package, see [fmt.Print]).
+
+Elements implementing the error interface are printed as their messages
+returned by the Error method, quoted in the Go-syntax mode. Nil errors are
+printed as <nil>.
+
+With the [PrintDeref] flag, pointer items (like elements of []*User) are printed
+as the values they point to, structures are printed as their exported fields
+with names, e.g. {Name:Alice Age:30}, nil pointers are printed as <nil>:
+
+  debug.PrintSlice([]*user{ {Name: "Alice", Age: 30}, nil }, debug.PrintDeref)
+
+will produce:
+
+  [#0:{Name:Alice Age:30} #1:<nil>]
+
+With the [PrintStats] flag, slices of numbers (including named numeric types)
+are followed by the summary line with the aggregates of all items of the slice,
+also when only a part of the items is printed, e.g. by [PrintSliceRange]:
+
+  debug.PrintSlice([]int{3, 1, 4, 1, 5}, debug.PrintStats)
+
+will produce:
+
+  [#0:3 #1:1 #2:4 #3:1 #4:5]
+  stats: count=5 min=1 max=5 sum=14 mean=2.8
+
+The flag is ignored for slices of other types.
+
+With the [PrintLiteral] flag, the slice is printed as a Go composite literal
+that can be pasted into code, e.g. to capture a test fixture:
+
+  debug.PrintSlice([]point{ {1, 2}, {3, 4} }, debug.PrintLiteral)
+
+will produce:
+
+  []main.point{main.point{X: 1, Y: 2}, main.point{X: 3, Y: 4}}
+
+Nested slices, arrays, maps, structures and pointers to them are rendered
+recursively, values stored in interfaces are converted to their dynamic types.
+Zero fields of structures are omitted, map entries are sorted. Named types are
+qualified by their package names, the qualifier should be removed if the literal
+is used in the same package. Channels and functions are rendered as nil, as well
+as pointers making cycles. Fields unexported from other packages (e.g. fields of
+time.Time) cannot be set in the compilable code. Only [PrintValPerLine] is
+applied along with PrintLiteral to print one element per line, other flags are ignored.
+
 See more examples in the Examples section.
 
 */
 func PrintSlice[T any](slice []T, flagsVariadic ...PrintFlags) {
+	printSlice(slice, 0, len(slice), -1, mergeFlags(flagsVariadic))
+}
+
+// printSlice outputs items of the slice in range [lo, hi), the item with the
+// mark index is marked by the windowMark, use -1 to mark nothing. With the
+// PrintLiteral flag, the items of the range are printed as the composite literal
+func printSlice[T any](slice []T, lo, hi, mark int, flags PrintFlags) {
+	if flags.Is(PrintLiteral) {
+		printLiteral(slice[lo:hi], flags.Is(PrintValPerLine))
+		return
+	}
+
+	// Output buffer, it is written to the output at once
+	buf := &strings.Builder{}
+
 	// Open/closed braces
 	obr, cbr := "[", "]"
 
-	// Get flags if specified
-	flags := mergeFlags(flagsVariadic)
-
 	// Is printing of slice type required?
 	if flags.Is(PrintType) {
 		// Print slice type
-		fmt.Printf("%T", slice)
+		fmt.Fprintf(buf, "%T", slice)
 		// Replace open/closed braces to make Go-like output
 		obr, cbr = "{", "}"
 	}
 
 	// Is printing of length and capacity required?
 	if flags.Is(PrintLenCap) {
-		fmt.Printf("(%d:%d)", len(slice), cap(slice))
+		fmt.Fprintf(buf, "(%d:%d)", len(slice), cap(slice))
 	}
 
 	// Output format
-	outFmt := itemFmt(flags)
+	outFmt := itemFmt(flags, len(slice), elemSize[T]())
 
 	// Print open brace
-	fmt.Print(obr)
+	buf.WriteString(obr)
 
 	// Is only one value per line to be printed?
 	if flags.Is(PrintValPerLine) {
 		// Print new line before the first item
-		fmt.Println()
+		buf.WriteString("\n")
 	}
 
 	// Output items
-	printSliceItems(outFmt, slice, flags)
+	printSliceItems(buf, outFmt, slice, flags, lo, hi, mark)
 
 	// Print closed brace
-	fmt.Println(cbr)
+	buf.WriteString(cbr + "\n")
+
+	// Is printing of the aggregates of numeric items required?
+	if flags.Is(PrintStats) {
+		if stats, ok := sliceStats(slice); ok {
+			buf.WriteString(stats + "\n")
+		}
+	}
+
+	writeOutput(buf.String())
 }
 
-func itemFmt(flags PrintFlags) string {
+// itemFmt returns the format of the item prefix, the format takes arguments:
+// position, value type specificator and byte offset of the item
+func itemFmt(flags PrintFlags, n int, size uintptr) string {
 	// Output format
 	outFmt := ""
 
@@ -105,28 +197,171 @@ func itemFmt(flags PrintFlags) string {
 		outFmt += "  "
 	}
 
+	// Is printing of the byte offset required?
+	if flags.Is(PrintOffset) {
+		// Pad the offset to the even width of the largest offset
+		width := 2
+		if n > 1 {
+			width = len(strconv.FormatUint(uint64(uintptr(n - 1) * size), 16))
+			width += width % 2
+		}
+		outFmt += "0x%0" + strconv.Itoa(width) + "[3]x "
+	}
+
 	// Is printing sharp has not disabled?
 	if flags.Not(PrintNoSharp) {
 		// Append sharp sign
 		outFmt += "#"
 	}
 
-	// Appnd position, value type specificator and colon before the value
-	outFmt += "%d%s:"
+	// Is printing of the position in hex required?
+	if flags.Is(PrintHexIndex) {
+		outFmt += "0x"
+		if flags.Is(PrintPadIndex) && n > 1 {
+			// Pad the position to the width of the largest position in hex
+			outFmt += "%0" + strconv.Itoa(len(strconv.FormatInt(int64(n - 1), 16))) + "[1]x%[2]s:"
+		} else {
+			outFmt += "%[1]x%[2]s:"
+		}
+	} else if flags.Is(PrintPadIndex) && n > 1 {
+		// Pad the position to the width of the largest position
+		outFmt += "%0" + strconv.Itoa(len(strconv.Itoa(n - 1))) + "[1]d%[2]s:"
+	} else {
+		// Appnd position, value type specificator and colon before the value
+		outFmt += "%[1]d%[2]s:"
+	}
+
+	return outFmt
+}
+
+func formatValue(v any, flags PrintFlags) string {
+	// Value output format
+	valFmt := "%v"
 
 	// Is Go-syntax required in output?
 	if flags.Is(PrintGoSyntax) {
-		// Append alternative value output format
-		outFmt += "%#v"
+		// Use alternative value output format
+		valFmt = "%#v"
+	}
+
+	var out string
+	// Is it a time to be printed using the layout?
+	if ts, ok := formatTime(v); ok && flags.Is(PrintTimeLayout) {
+		if flags.Is(PrintGoSyntax) {
+			out = strconv.Quote(ts)
+		} else {
+			out = ts
+		}
+	} else if str, ok := stringerValue(v); ok && flags.Is(PrintStringer) {
+		// Use the String method regardless of the Go-syntax mode
+		out = str
+	} else if msg, ok := errorMessage(v); ok {
+		// It is an error, render its message in both modes
+		if flags.Is(PrintGoSyntax) && msg != nilToken {
+			out = strconv.Quote(msg)
+		} else {
+			out = msg
+		}
+	} else if lv, ok := atomicLoad(v); ok {
+		// It is an atomic wrapper
+		if flags.Is(PrintGoSyntax) {
+			// Use the wrapper type as a conversion
+			out = fmt.Sprintf("%T(%#v)", v, lv)
+		} else {
+			// Print the stored value only
+			out = fmt.Sprintf(valFmt, lv)
+		}
+	} else if dv, ok := derefValue(v); ok && flags.Is(PrintDeref) {
+		// Print the pointed value instead of the pointer
+		out = formatDeref(dv, flags)
 	} else {
-		// Append default value output format
-		outFmt += "%v"
+		out = fmt.Sprintf(valFmt, v)
 	}
 
-	return outFmt
+	// Is printing of the pointer address required?
+	if flags.Is(PrintAddr) && reflect.ValueOf(v).Kind() == reflect.Pointer {
+		// Append the address of the pointer
+		out += fmt.Sprintf("@%p", v)
+	}
+
+	return out
+}
+
+// nilToken is printed for nil error values
+const nilToken = "<nil>"
+
+// errorMessage returns the message of v if it is an error, nil errors
+// (including nil pointers implementing error) are rendered as nilToken
+func errorMessage(v any) (string, bool) {
+	err, ok := v.(error)
+	if !ok {
+		return "", false
+	}
+
+	if rv := reflect.ValueOf(err); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		// Error method may not support nil receivers
+		return nilToken, true
+	}
+
+	return err.Error(), true
+}
+
+// stringerValue returns the result of the String method of v if it implements
+// fmt.Stringer, nil pointers implementing fmt.Stringer are rendered as nilToken
+func stringerValue(v any) (string, bool) {
+	s, ok := v.(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+
+	if rv := reflect.ValueOf(s); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		// String method may not support nil receivers
+		return nilToken, true
+	}
+
+	return s.String(), true
+}
+
+// elemSize returns the size of the element of type T in bytes, the same as unsafe.Sizeof
+func elemSize[T any]() uintptr {
+	return reflect.TypeOf((*T)(nil)).Elem().Size()
+}
+
+// atomicLoad returns the value stored in v if it is an atomic wrapper from the
+// sync/atomic package (e.g. atomic.Int64 or atomic.Pointer[T]) or a non-nil
+// pointer to it, such values are printed as their stored values
+func atomicLoad(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() || !isAtomic(rv.Type().Elem()) {
+			return nil, false
+		}
+	} else {
+		if !rv.IsValid() || !isAtomic(rv.Type()) {
+			return nil, false
+		}
+
+		// Need an addressable copy to call the method with pointer receiver
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		rv = p
+	}
+
+	return rv.MethodByName("Load").Call(nil)[0].Interface(), true
+}
+
+// isAtomic returns true if t is one of the sync/atomic wrapper types
+func isAtomic(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "sync/atomic" {
+		return false
+	}
+
+	_, ok := reflect.PointerTo(t).MethodByName("Load")
+
+	return ok
 }
 
-func printSliceItems[T any](outFmt string, slice []T, flags PrintFlags) {
+func printSliceItems[T any](buf *strings.Builder, outFmt string, slice []T, flags PrintFlags, lo, hi, mark int) {
 	// Items divider
 	var iDiv string
 	if flags.Is(PrintValPerLine) {
@@ -134,13 +369,24 @@ func printSliceItems[T any](outFmt string, slice []T, flags PrintFlags) {
 		iDiv = "\n"
 
 		// Also need to print new line at end of the output
-		defer fmt.Println()
+		defer buf.WriteString("\n")
 	} else {
 		// Use space as items separator
 		iDiv = " "
 	}
 
-	for i, v := range slice {
+	for i := lo; i < hi; {
+		v := slice[i]
+
+		// Number of consecutive equal elements
+		count := 1
+		// Is collapsing of equal elements required? The marked item is never collapsed
+		if flags.Is(PrintRunLength) && i != mark {
+			for i + count < hi && i + count != mark && itemsEqual(v, slice[i + count]) {
+				count++
+			}
+		}
+
 		// Type of value string
 		var valType string
 		// Is it required?
@@ -149,13 +395,30 @@ func printSliceItems[T any](outFmt string, slice []T, flags PrintFlags) {
 			valType = fmt.Sprintf("(%T)", v)
 		}
 
-		fmt.Printf(outFmt, i, valType, v)
+		prefix := fmt.Sprintf(outFmt, i, valType, uintptr(i) * elemSize[T]())
+		if i == mark {
+			if flags.Is(PrintValPerLine) {
+				// Replace the indentation to keep items aligned
+				prefix = windowMark + prefix[len(windowMark):]
+			} else {
+				prefix = windowMark + prefix
+			}
+		}
+		buf.WriteString(prefix)
+		buf.WriteString(formatValue(v, flags))
+
+		if count > 1 {
+			// Print number of collapsed elements
+			fmt.Fprintf(buf, " (×%d)", count)
+		}
+
+		i += count
 
-		if i != len(slice) - 1 {
+		if i != hi {
 			if flags.Is(PrintCommaSep) {
-				fmt.Print(",")
+				buf.WriteString(",")
 			}
-			fmt.Print(iDiv)
+			buf.WriteString(iDiv)
 		}
 	}
 }
@@ -182,3 +445,21 @@ func mergeFlags(flagsVariadic []PrintFlags) PrintFlags {
 		return flags
 	}
 }
+
+// itemsEqual compares a and b using == operator if their type is comparable,
+// otherwise reflect.DeepEqual is used
+func itemsEqual(a, b any) (eq bool) {
+	defer func() {
+		// Comparable types with interface fields can panic
+		// if interface values are not comparable
+		if recover() != nil {
+			eq = reflect.DeepEqual(a, b)
+		}
+	}()
+
+	if t := reflect.TypeOf(a); t != nil && t.Comparable() {
+		return a == b
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -1,5 +1,13 @@
 package debug
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
 func Example_printSliceDefault() {
 	slice := []string{"one", "two", "three"}
 
@@ -37,6 +45,17 @@ func Example_printSliceGoSyntaxValType() {
 	// [#0(map[string]int):map[string]int{"one":1} #1(map[string]int):map[string]int{"two":2} #2(map[string]int):map[string]int{"three":3}]
 }
 
+func Example_printSliceSortedMaps() {
+	slice := []map[string]int{ {"c": 3, "a": 1, "b": 2}, {"z": 26, "y": 25, "x": 24} }
+
+	PrintSlice(slice)
+	PrintSlice(slice, PrintGoSyntax)
+
+	// Output:
+	// [#0:map[a:1 b:2 c:3] #1:map[x:24 y:25 z:26]]
+	// [#0:map[string]int{"a":1, "b":2, "c":3} #1:map[string]int{"x":24, "y":25, "z":26}]
+}
+
 func Example_printSliceCommaSepNoSharp() {
 	slice := []int{1, 1, 2, 3, 5, 8}
 
@@ -86,3 +105,169 @@ func Example_printSliceStructs() {
 	// Output:
 	// [#0:debug.eventInfo{cond:true, amount:5, avg:3.434, descr:"positive condition", pos:debug.point{x:15, y:83}}]
 }
+
+func Example_printSliceAddr() {
+	// Addresses of non-nil pointers are different on each run,
+	// so use nil pointers to get a reproducible output
+	var p1, p2 *int
+	slice := []*int{p1, p2}
+
+	PrintSlice(slice, PrintAddr)
+
+	// Output:
+	// [#0:<nil>@0x0 #1:<nil>@0x0]
+}
+
+func Example_printSliceRunLength() {
+	slice := []int{1, 1, 1, 2, 3, 3, 1}
+
+	PrintSlice(slice, PrintRunLength)
+
+	// Non-comparable elements are compared by reflect.DeepEqual
+	nested := [][]string{{"a"}, {"a"}, {"b"}}
+
+	PrintSlice(nested, PrintRunLength, PrintNoSharp, PrintCommaSep)
+
+	// Output:
+	// [#0:1 (×3) #3:2 #4:3 (×2) #6:1]
+	// [0:[a] (×2), 2:[b]]
+}
+
+func Example_printSlicePadIndex() {
+	slice := make([]int, 12)
+	for i := range slice {
+		slice[i] = i * i
+	}
+
+	PrintSlice(slice[:3], PrintPadIndex)
+	PrintSlice(slice, PrintPadIndex, PrintValPerLine)
+
+	// Output:
+	// [#0:0 #1:1 #2:4]
+	// [
+	//   #00:0
+	//   #01:1
+	//   #02:4
+	//   #03:9
+	//   #04:16
+	//   #05:25
+	//   #06:36
+	//   #07:49
+	//   #08:64
+	//   #09:81
+	//   #10:100
+	//   #11:121
+	// ]
+}
+
+func Example_printSliceHexIndex() {
+	buf := []byte("Hello, hexadecimal")
+
+	PrintSlice(buf[:3], PrintHexIndex)
+	PrintSliceRange(buf, 8, 12, PrintHexIndex, PrintPadIndex, PrintNoSharp)
+
+	// Output:
+	// [#0x0:72 #0x1:101 #0x2:108]
+	// [0x08:101 0x09:120 0x0a:97 0x0b:100]
+}
+
+func Example_printSliceAtomic() {
+	counters := []*atomic.Int64{{}, {}, {}}
+	for i, c := range counters {
+		c.Store(int64(i * 10))
+	}
+
+	PrintSlice(counters)
+	PrintSlice(counters[:1], PrintGoSyntax)
+
+	// Output:
+	// [#0:0 #1:10 #2:20]
+	// [#0:*atomic.Int64(0)]
+}
+
+func Example_printSliceOffset() {
+	words := []int32{7, 8, 9}
+
+	PrintSlice(words, PrintOffset)
+	PrintSlice([]byte("Hi!"), PrintOffset, PrintValPerLine, PrintNoSharp)
+
+	// Output:
+	// [0x00 #0:7 0x04 #1:8 0x08 #2:9]
+	// [
+	//   0x00 0:72
+	//   0x01 1:105
+	//   0x02 2:33
+	// ]
+}
+
+func Example_printSliceErrors() {
+	var nilErr *os.PathError
+	errs := []error{errors.New("first"), nil, fmt.Errorf("wrapped: %w", io.EOF), nilErr}
+
+	PrintSlice(errs)
+	PrintSlice(errs, PrintGoSyntax, PrintValType)
+
+	// Output:
+	// [#0:first #1:<nil> #2:wrapped: EOF #3:<nil>]
+	// [#0(*errors.errorString):"first" #1(<nil>):<nil> #2(*fmt.wrapError):"wrapped: EOF" #3(*fs.PathError):<nil>]
+}
+
+type color int
+
+const (
+	red color = iota
+	green
+	blue
+)
+
+func (c color) String() string {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
+func Example_printSliceStringer() {
+	colors := []color{red, blue, green}
+
+	PrintSlice(colors, PrintGoSyntax, PrintValType)
+	PrintSlice(colors, PrintGoSyntax, PrintValType, PrintStringer)
+
+	// Output:
+	// [#0(debug.color):0 #1(debug.color):2 #2(debug.color):1]
+	// [#0(debug.color):red #1(debug.color):blue #2(debug.color):green]
+}
+
+func Example_printSliceDeref() {
+	type user struct {
+		Name	string
+		Age		int
+		token	string
+	}
+	users := []*user{ {Name: "Alice", Age: 30, token: "secret"}, nil, {Name: "Bob", Age: 25} }
+
+	PrintSlice(users, PrintDeref)
+	PrintSlice(users[:2], PrintDeref, PrintGoSyntax, PrintValPerLine)
+
+	// Output:
+	// [#0:{Name:Alice Age:30} #1:<nil> #2:{Name:Bob Age:25}]
+	// [
+	//   #0:debug.user{Name:"Alice", Age:30}
+	//   #1:<nil>
+	// ]
+}
+
+func Example_printSliceStats() {
+	PrintSlice([]int{3, 1, 4, 1, 5}, PrintStats)
+	PrintSliceRange([]float64{0.5, -2, 8, 1.5}, 0, 2, PrintStats)
+	PrintSlice([]uint8{}, PrintStats)
+
+	// Non-numeric slices are printed as usual
+	PrintSlice([]string{"a", "b"}, PrintStats)
+
+	// Output:
+	// [#0:3 #1:1 #2:4 #3:1 #4:5]
+	// stats: count=5 min=1 max=5 sum=14 mean=2.8
+	// [#0:0.5 #1:-2]
+	// stats: count=4 min=-2 max=8 sum=8 mean=2
+	// []
+	// stats: count=0
+	// [#0:a #1:b]
+}
@@ -0,0 +1,81 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// sliceStats returns the summary line printed with the PrintStats flag, false
+// is returned if the elements of the slice are not numbers
+func sliceStats(slice any) (string, bool) {
+	sv := reflect.ValueOf(slice)
+	n := sv.Len()
+
+	// Aggregates formatted according to the kind of the elements
+	var min, max, sum string
+	var mean float64
+
+	switch sv.Type().Elem().Kind() { //nolint:exhaustive	// other kinds are not numbers
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var lo, hi, total int64
+		for i := 0; i < n; i++ {
+			x := sv.Index(i).Int()
+			if i == 0 || x < lo {
+				lo = x
+			}
+			if i == 0 || x > hi {
+				hi = x
+			}
+			total += x
+		}
+		min, max, sum = strconv.FormatInt(lo, 10), strconv.FormatInt(hi, 10), strconv.FormatInt(total, 10)
+		mean = float64(total)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var lo, hi, total uint64
+		for i := 0; i < n; i++ {
+			x := sv.Index(i).Uint()
+			if i == 0 || x < lo {
+				lo = x
+			}
+			if i == 0 || x > hi {
+				hi = x
+			}
+			total += x
+		}
+		min, max, sum = strconv.FormatUint(lo, 10), strconv.FormatUint(hi, 10), strconv.FormatUint(total, 10)
+		mean = float64(total)
+
+	case reflect.Float32, reflect.Float64:
+		var lo, hi, total float64
+		for i := 0; i < n; i++ {
+			x := sv.Index(i).Float()
+			if i == 0 || x < lo {
+				lo = x
+			}
+			if i == 0 || x > hi {
+				hi = x
+			}
+			total += x
+		}
+		min, max, sum = floatString(lo), floatString(hi), floatString(total)
+		mean = total
+
+	default:
+		return "", false
+	}
+
+	if n == 0 {
+		// Nothing to aggregate
+		return "stats: count=0", true
+	}
+
+	return fmt.Sprintf("stats: count=%d min=%s max=%s sum=%s mean=%s",
+		n, min, max, sum, floatString(mean / float64(n))), true
+}
+
+// floatString returns the shortest representation of f
+func floatString(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
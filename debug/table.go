@@ -0,0 +1,102 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Padding between the table columns
+const tablePadding = 2
+
+/*
+PrintTable outputs a slice of structures as a table with one column per exported
+field of the structure. The first row is a header with the field names, then
+one row per element follows, each row starts with the ordinal number of the
+element. For example,
+
+  type user struct {
+      Name  string
+      Age   int
+  }
+  debug.PrintTable([]user{ {"Alice", 31}, {"Bob", 7} })
+
+will produce:
+
+      Name   Age
+  #0  Alice  31
+  #1  Bob    7
+
+The flags are the same as used by [PrintSlice], but only [PrintGoSyntax],
+[PrintNoSharp] and [PrintAddr] affect the output. Cell values containing tabs
+or new lines are quoted to keep the table aligned.
+
+PrintTable returns an error if slice is not a slice (or array) of structures
+or the structure has no exported fields, nothing is printed in this case.
+*/
+func PrintTable(slice any, flagsVariadic ...PrintFlags) error {
+	flags := mergeFlags(flagsVariadic)
+
+	sv := reflect.ValueOf(slice)
+	if sv.Kind() != reflect.Slice && sv.Kind() != reflect.Array {
+		return fmt.Errorf("PrintTable: argument of type %T is not a slice", slice)
+	}
+
+	st := sv.Type().Elem()
+	if st.Kind() != reflect.Struct {
+		return fmt.Errorf("PrintTable: element type %v is not a structure", st)
+	}
+
+	// Indexes of exported fields
+	var fields []int
+	for i := 0; i < st.NumField(); i++ {
+		if st.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+	if fields == nil {
+		return fmt.Errorf("PrintTable: structure %v has no exported fields", st)
+	}
+
+	buf := &strings.Builder{}
+	tw := tabwriter.NewWriter(buf, 0, 0, tablePadding, ' ', 0)
+
+	// Print header, the index column has no name
+	for _, i := range fields {
+		fmt.Fprintf(tw, "\t%s", st.Field(i).Name)
+	}
+	fmt.Fprintln(tw)
+
+	// Print rows
+	for n := 0; n < sv.Len(); n++ {
+		if flags.Not(PrintNoSharp) {
+			fmt.Fprint(tw, "#")
+		}
+		fmt.Fprint(tw, n)
+
+		for _, i := range fields {
+			fmt.Fprintf(tw, "\t%s", tableCell(sv.Index(n).Field(i).Interface(), flags))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	// Errors are not possible on writing to strings.Builder
+	_ = tw.Flush()
+
+	writeOutput(buf.String())
+
+	return nil
+}
+
+// tableCell returns the formatted value v as a table cell
+func tableCell(v any, flags PrintFlags) string {
+	cell := formatValue(v, flags)
+	if strings.ContainsAny(cell, "\t\n") {
+		// Quote to keep the table aligned
+		cell = strconv.Quote(cell)
+	}
+
+	return cell
+}
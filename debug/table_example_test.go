@@ -0,0 +1,38 @@
+package debug
+
+import (
+	"fmt"
+)
+
+func ExamplePrintTable() {
+	type point struct { X, Y int }
+	type eventInfo struct {
+		Cond	bool
+		Amount	int
+		Descr	string
+		Pos		point
+		notes	string
+	}
+	slice := []eventInfo{
+		{Cond: true, Amount: 5, Descr: "positive condition", Pos: point{X: 15, Y: 83}},
+		{Amount: 125, Descr: "two\nlines", notes: "not printed"},
+	}
+
+	if err := PrintTable(slice); err != nil {
+		fmt.Println(err)
+	}
+	if err := PrintTable(slice[:1], PrintGoSyntax, PrintNoSharp); err != nil {
+		fmt.Println(err)
+	}
+	if err := PrintTable([]int{1, 2}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	//     Cond   Amount  Descr               Pos
+	// #0  true   5       positive condition  {15 83}
+	// #1  false  125     "two\nlines"        {0 0}
+	//    Cond  Amount  Descr                 Pos
+	// 0  true  5       "positive condition"  debug.point{X:15, Y:83}
+	// PrintTable: element type int is not a structure
+}
@@ -0,0 +1,41 @@
+package debug
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Layout of time.Time values printed with the PrintTimeLayout flag
+var timeLayout atomic.Value // string
+
+/*
+SetTimeLayout sets the layout used to print [time.Time] values if the
+[PrintTimeLayout] flag is set, see [time.Time.Format] for the layout syntax.
+Passing an empty string restores the default layout [time.RFC3339]. For example:
+
+  debug.SetTimeLayout(time.Kitchen)
+  defer debug.SetTimeLayout("")
+
+  debug.PrintSlice(times, debug.PrintTimeLayout)
+
+SetTimeLayout is safe for concurrent use with the Print* functions.
+*/
+func SetTimeLayout(layout string) {
+	timeLayout.Store(layout)
+}
+
+// formatTime returns v formatted by the current time layout, it returns
+// false if v is not a time.Time value
+func formatTime(v any) (string, bool) {
+	t, ok := v.(time.Time)
+	if !ok {
+		return "", false
+	}
+
+	layout, _ := timeLayout.Load().(string)
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	return t.Format(layout), true
+}
@@ -0,0 +1,26 @@
+package debug
+
+import (
+	"time"
+)
+
+func ExampleSetTimeLayout() {
+	times := []time.Time{
+		time.Date(2023, time.March, 1, 10, 30, 0, 0, time.UTC),
+		time.Date(2023, time.March, 2, 18, 5, 0, 0, time.UTC),
+	}
+
+	// Default layout is time.RFC3339
+	PrintSlice(times, PrintTimeLayout, PrintCommaSep, PrintNoSharp)
+
+	SetTimeLayout(time.Kitchen)
+	defer SetTimeLayout("")
+
+	PrintSlice(times, PrintTimeLayout)
+	PrintSlice(times, PrintTimeLayout, PrintGoSyntax)
+
+	// Output:
+	// [0:2023-03-01T10:30:00Z, 1:2023-03-02T18:05:00Z]
+	// [#0:10:30AM #1:6:05PM]
+	// [#0:"10:30AM" #1:"6:05PM"]
+}
@@ -0,0 +1,78 @@
+package debug
+
+// windowMark marks the center item printed by PrintSliceWindow
+const windowMark = "->"
+
+/*
+PrintSliceWindow works like [PrintSlice], but outputs only the items of the
+slice with indices in the range [center-radius, center+radius] clamped to the
+slice bounds. The items keep their ordinal numbers in the whole slice, the
+center item is marked by the "->" arrow. It is useful to see the neighborhood
+of a specific item of a large slice. For example,
+
+  ints := []int{0, 10, 20, 30, 40, 50, 60}
+  debug.PrintSliceWindow(ints, 5, 2)
+
+will produce:
+
+  [#3:30 #4:40 ->#5:50 #6:60]
+
+The flags are the same as used by PrintSlice, the length and capacity printed
+with [PrintLenCap] are of the whole slice. If the range does not intersect the
+slice, no items are printed. With [PrintLiteral], the items of the range are
+printed as the composite literal the same way as by PrintSlice, without the
+ordinal numbers and the arrow.
+*/
+func PrintSliceWindow[T any](slice []T, center, radius int, flagsVariadic ...PrintFlags) {
+	if radius < 0 {
+		radius = 0
+	}
+
+	lo, hi := center - radius, center + radius + 1
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(slice) {
+		hi = len(slice)
+	}
+	if lo > hi {
+		// No intersection with the slice
+		lo = hi
+	}
+
+	printSlice(slice, lo, hi, center, mergeFlags(flagsVariadic))
+}
+
+/*
+PrintSliceRange works like [PrintSlice], but outputs only the items of the
+slice with indices in the half-open range [lo, hi) clamped to the slice bounds.
+The items keep their ordinal numbers in the whole slice, so the output of
+large slices can be paged through by consecutive ranges. For example,
+
+  ints := []int{0, 10, 20, 30, 40, 50, 60}
+  debug.PrintSliceRange(ints, 2, 5)
+
+will produce:
+
+  [#2:20 #3:30 #4:40]
+
+The flags are the same as used by PrintSlice, the length and capacity printed
+with [PrintLenCap] are of the whole slice. If lo >= hi or the range does not
+intersect the slice, no items are printed. With [PrintLiteral], the items of the
+range are printed as the composite literal the same way as by PrintSlice, so a
+part of a large slice can be captured as a test fixture.
+*/
+func PrintSliceRange[T any](slice []T, lo, hi int, flagsVariadic ...PrintFlags) {
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(slice) {
+		hi = len(slice)
+	}
+	if lo > hi {
+		// Empty range or no intersection with the slice
+		lo = hi
+	}
+
+	printSlice(slice, lo, hi, -1, mergeFlags(flagsVariadic))
+}
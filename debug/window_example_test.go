@@ -0,0 +1,38 @@
+package debug
+
+func ExamplePrintSliceWindow() {
+	ints := []int{0, 10, 20, 30, 40, 50, 60}
+
+	PrintSliceWindow(ints, 5, 2)
+	PrintSliceWindow(ints, 1, 1, PrintValPerLine, PrintLenCap)
+	PrintSliceWindow(ints, 5, 1, PrintLiteral)
+
+	// Output:
+	// [#3:30 #4:40 ->#5:50 #6:60]
+	// (7:7)[
+	//   #0:0
+	// ->#1:10
+	//   #2:20
+	// ]
+	// []int{40, 50, 60}
+}
+
+func ExamplePrintSliceRange() {
+	ints := []int{0, 10, 20, 30, 40, 50, 60}
+
+	PrintSliceRange(ints, 2, 5)
+	PrintSliceRange(ints, 5, 100, PrintLenCap, PrintCommaSep)
+	PrintSliceRange(ints, 4, 2, PrintLenCap)
+	PrintSliceRange(ints, 2, 4, PrintLiteral)
+	PrintSliceRange(ints, 1, 3, PrintLiteral, PrintValPerLine)
+
+	// Output:
+	// [#2:20 #3:30 #4:40]
+	// (7:7)[#5:50, #6:60]
+	// (7:7)[]
+	// []int{20, 30}
+	// []int{
+	// 	10,
+	// 	20,
+	// }
+}